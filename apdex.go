@@ -0,0 +1,167 @@
+package metrics
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Apdex is a metric that calculates Application Performance Index of response durations.
+// Responses not longer than target threshold T are satisfied, not longer than 4T are tolerating,
+// others are frustrated. The score is (satisfied + tolerating/2) / total and lays in range [0, 1].
+// Snapshots hold the score per interval with raw counts.
+// Satsfies Metric interface.
+type Apdex struct {
+	name string
+	described
+	target time.Duration
+
+	mu         sync.Mutex
+	satisfied  uint64
+	tolerating uint64
+	frustrated uint64
+}
+
+// NewApdex returns new Apdex metric with target threshold that satsfies Metric interface.
+// Its score is formatted with two decimals unless other formatter is set by options.
+func NewApdex(name string, target time.Duration, opts ...Option) *Apdex {
+	opts = append([]Option{WithFormatter(FormatFixed(2))}, opts...)
+	return &Apdex{name: name, described: newDescribed(KindGauge, opts), target: target}
+}
+
+// Update counts response duration.
+func (a *Apdex) Update(d time.Duration) {
+	a.mu.Lock()
+	switch {
+	case d <= a.target:
+		a.satisfied++
+	case d <= 4*a.target:
+		a.tolerating++
+	default:
+		a.frustrated++
+	}
+	a.mu.Unlock()
+}
+
+// UpdateSince counts duration of response started at ts.
+func (a *Apdex) UpdateSince(ts time.Time) {
+	a.Update(time.Since(ts))
+}
+
+// Target returns target threshold T.
+func (a *Apdex) Target() time.Duration {
+	return a.target
+}
+
+// Get returns Apdex score. It returns NaN if there were no responses.
+func (a *Apdex) Get() interface{} {
+	return a.Value()
+}
+
+// Value returns Apdex score. It returns NaN if there were no responses.
+func (a *Apdex) Value() float64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.score()
+}
+
+// Sample returns Apdex score with counts of satisfied, tolerating and frustrated responses.
+func (a *Apdex) Sample() Sample {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return Sample{
+		Name:  a.name,
+		Kind:  a.meta.Kind,
+		Value: a.score(),
+		Fields: map[string]float64{
+			"satisfied":  float64(a.satisfied),
+			"tolerating": float64(a.tolerating),
+			"frustrated": float64(a.frustrated),
+			"count":      float64(a.satisfied + a.tolerating + a.frustrated),
+		},
+	}
+}
+
+// Counts returns counts of satisfied, tolerating and frustrated responses.
+func (a *Apdex) Counts() (satisfied, tolerating, frustrated uint64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.satisfied, a.tolerating, a.frustrated
+}
+
+// String returns formated representation of Apdex score with counts,
+// e.g. "0.94 satisfied=90 tolerating=8 frustrated=2".
+func (a *Apdex) String() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	score := "NaN"
+	if v := a.score(); !math.IsNaN(v) {
+		score = a.formatFloat(v)
+	}
+	return score +
+		" satisfied=" + strconv.FormatUint(a.satisfied, 10) +
+		" tolerating=" + strconv.FormatUint(a.tolerating, 10) +
+		" frustrated=" + strconv.FormatUint(a.frustrated, 10)
+}
+
+// Name returns metric name.
+func (a *Apdex) Name() string {
+	return a.name
+}
+
+// Copy returns copy of Apdex metric. It needs for snapshots.
+func (a *Apdex) Copy() Metric {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.copy()
+}
+
+// Reset flushes counts of responses. It needs for snapshots.
+func (a *Apdex) Reset() {
+	a.mu.Lock()
+	a.satisfied, a.tolerating, a.frustrated = 0, 0, 0
+	a.mu.Unlock()
+}
+
+// Swap returns copy of Apdex metric and flushes its counts atomically. It needs for snapshots.
+func (a *Apdex) Swap() Metric {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	cp := a.copy()
+	a.satisfied, a.tolerating, a.frustrated = 0, 0, 0
+	return cp
+}
+
+// Diff returns Apdex metric of responses counted since prev. It needs for cumulative snapshots.
+// If any count is less than prev (e.g. it was reset) the current counts are returned.
+func (a *Apdex) Diff(prev Metric) Metric {
+	cp := a.Copy().(*Apdex)
+	p, ok := prev.(*Apdex)
+	if ok && p.satisfied <= cp.satisfied && p.tolerating <= cp.tolerating && p.frustrated <= cp.frustrated {
+		cp.satisfied -= p.satisfied
+		cp.tolerating -= p.tolerating
+		cp.frustrated -= p.frustrated
+	}
+	return cp
+}
+
+func (a *Apdex) copy() *Apdex {
+	return &Apdex{
+		name:       a.name,
+		described:  a.described,
+		target:     a.target,
+		satisfied:  a.satisfied,
+		tolerating: a.tolerating,
+		frustrated: a.frustrated,
+	}
+}
+
+// score returns Apdex score of counted responses.
+func (a *Apdex) score() float64 {
+	total := a.satisfied + a.tolerating + a.frustrated
+	if total == 0 {
+		return math.NaN()
+	}
+	return (float64(a.satisfied) + float64(a.tolerating)/2) / float64(total)
+}
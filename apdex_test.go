@@ -0,0 +1,46 @@
+package metrics_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/admobi/easy-metrics"
+)
+
+func TestApdex(t *testing.T) {
+	a := metrics.NewApdex("tapdex", 100*time.Millisecond)
+	if v := a.Get().(float64); !math.IsNaN(v) {
+		t.Errorf("apdex without responses is expected to be NaN, but got %v", v)
+	}
+
+	for i := 0; i < 6; i++ {
+		a.Update(50 * time.Millisecond)
+	}
+	a.Update(100 * time.Millisecond)
+	a.Update(400 * time.Millisecond)
+	a.Update(300 * time.Millisecond)
+	a.Update(time.Second)
+
+	s, tol, f := a.Counts()
+	if s != 7 || tol != 2 || f != 1 {
+		t.Errorf("apdex counts are expected to be 7/2/1, but got %d/%d/%d", s, tol, f)
+	}
+	if v := a.Get().(float64); v != 0.8 {
+		t.Errorf("apdex score is expected to be 0.8, but got %v", v)
+	}
+	if str := a.String(); str != "0.80 satisfied=7 tolerating=2 frustrated=1" {
+		t.Errorf("unexpected Apdex string %q", str)
+	}
+	if smp, _ := metrics.SampleOf(a); smp.Fields["count"] != 10 || smp.Fields["frustrated"] != 1 {
+		t.Errorf("unexpected Apdex sample %v", smp)
+	}
+
+	cp := a.Swap().(*metrics.Apdex)
+	if v := cp.Value(); v != 0.8 {
+		t.Errorf("swapped Apdex score is expected to be 0.8, but got %v", v)
+	}
+	if s, tol, f := a.Counts(); s+tol+f != 0 {
+		t.Errorf("apdex should be flushed by swap, but got counts %d/%d/%d", s, tol, f)
+	}
+}
@@ -0,0 +1,47 @@
+//go:build go1.18
+// +build go1.18
+
+package metrics
+
+import (
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// NewBuildInfo returns info metric with build information of the running binary:
+// module path and version, git commit, commit time, modification flag, Go version and start time.
+// Values that aren't available, e.g. git commit of binary built without VCS stamping, are omitted.
+//
+//	r.AddMetrics(NewBuildInfo("build"))
+func NewBuildInfo(name string, opts ...Option) *Info {
+	i := NewInfo(name, map[string]string{
+		"go":         runtime.Version(),
+		"start_time": time.Now().Format(time.RFC3339),
+	}, opts...)
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return i
+	}
+	if bi.GoVersion != "" {
+		i.Set("go", bi.GoVersion)
+	}
+	if bi.Main.Path != "" {
+		i.Set("path", bi.Main.Path)
+	}
+	if bi.Main.Version != "" {
+		i.Set("version", bi.Main.Version)
+	}
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			i.Set("commit", s.Value)
+		case "vcs.time":
+			i.Set("commit_time", s.Value)
+		case "vcs.modified":
+			i.Set("modified", s.Value)
+		}
+	}
+	return i
+}
@@ -0,0 +1,22 @@
+//go:build go1.18
+// +build go1.18
+
+package metrics_test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/admobi/easy-metrics"
+)
+
+func TestBuildInfo(t *testing.T) {
+	i := metrics.NewBuildInfo("tbuildinfo")
+	values := i.Values()
+	if values["go"] != runtime.Version() {
+		t.Errorf("Go version is expected to be %s, but got %s", runtime.Version(), values["go"])
+	}
+	if values["start_time"] == "" {
+		t.Errorf("build info should contain start time, got %v", values)
+	}
+}
@@ -0,0 +1,206 @@
+package metrics
+
+import (
+	"hash/fnv"
+	"math"
+	"sync"
+)
+
+// Bounds of cardinality precision.
+const (
+	minCardinalityPrecision = 4
+	maxCardinalityPrecision = 16
+)
+
+// Cardinality is a metric that estimates count of unique values using HyperLogLog sketch.
+// Satsfies Metric interface.
+type Cardinality struct {
+	name string
+	described
+	precision uint8
+
+	mu        sync.Mutex
+	registers []uint8
+}
+
+// NewCardinality returns new cardinality metric that satsfies Metric interface.
+// Precision defines the number of sketch registers (2^precision) and is limited to range [4, 16].
+// The standard error of estimation is 1.04/sqrt(2^precision), e.g. 0.81% for precision 14.
+func NewCardinality(name string, precision uint8, opts ...Option) *Cardinality {
+	if precision < minCardinalityPrecision {
+		precision = minCardinalityPrecision
+	}
+	if precision > maxCardinalityPrecision {
+		precision = maxCardinalityPrecision
+	}
+	return &Cardinality{
+		name:      name,
+		described: newDescribed(KindGauge, opts),
+		precision: precision,
+		registers: make([]uint8, 1<<precision),
+	}
+}
+
+// Add adds value into sketch.
+func (c *Cardinality) Add(value []byte) {
+	h := fnv.New64a()
+	h.Write(value)
+	c.addHash(mix64(h.Sum64()))
+}
+
+// AddString adds string value into sketch.
+func (c *Cardinality) AddString(value string) {
+	c.Add([]byte(value))
+}
+
+// Merge merges other sketch into the current one, so it estimates count of unique values of both.
+// Sketches must have the same precision.
+func (c *Cardinality) Merge(other *Cardinality) error {
+	if c.precision != other.precision {
+		return ErrPrecisionMismatch{}
+	}
+	if c == other {
+		return nil
+	}
+
+	other.mu.Lock()
+	regs := append([]uint8(nil), other.registers...)
+	other.mu.Unlock()
+
+	c.mu.Lock()
+	for i, r := range regs {
+		if r > c.registers[i] {
+			c.registers[i] = r
+		}
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+// Get returns estimated count of unique values.
+func (c *Cardinality) Get() interface{} {
+	return c.Estimate()
+}
+
+// Value returns estimated count of unique values as float64.
+func (c *Cardinality) Value() float64 {
+	return float64(c.Estimate())
+}
+
+// Estimate returns estimated count of unique values.
+func (c *Cardinality) Estimate() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	m := float64(len(c.registers))
+	var sum float64
+	var zeros int
+	for _, r := range c.registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	var alpha float64
+	switch len(c.registers) {
+	case 16:
+		alpha = 0.673
+	case 32:
+		alpha = 0.697
+	case 64:
+		alpha = 0.709
+	default:
+		alpha = 0.7213 / (1 + 1.079/m)
+	}
+
+	est := alpha * m * m / sum
+	// small range correction
+	if est <= 2.5*m && zeros > 0 {
+		est = m * math.Log(m/float64(zeros))
+	}
+	return uint64(est + 0.5)
+}
+
+// String returns formated representation of estimated count.
+func (c *Cardinality) String() string {
+	return c.formatUint(c.Estimate())
+}
+
+// Name returns metric name.
+func (c *Cardinality) Name() string {
+	return c.name
+}
+
+// Copy returns copy of cardinality. It needs for snapshots.
+func (c *Cardinality) Copy() Metric {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return &Cardinality{
+		name:      c.name,
+		described: c.described,
+		precision: c.precision,
+		registers: append([]uint8(nil), c.registers...),
+	}
+}
+
+// Reset flushes cardinality sketch. It needs for snapshots.
+func (c *Cardinality) Reset() {
+	c.mu.Lock()
+	for i := range c.registers {
+		c.registers[i] = 0
+	}
+	c.mu.Unlock()
+}
+
+// Swap returns copy of cardinality and flushes its sketch atomically. It needs for snapshots.
+func (c *Cardinality) Swap() Metric {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cp := &Cardinality{
+		name:      c.name,
+		described: c.described,
+		precision: c.precision,
+		registers: c.registers,
+	}
+	c.registers = make([]uint8, len(cp.registers))
+	return cp
+}
+
+func (c *Cardinality) addHash(x uint64) {
+	idx := x >> (64 - c.precision)
+	// the guard bit limits rank by 64-precision+1
+	w := x<<c.precision | 1<<(c.precision-1)
+	rank := leadingZeros64(w) + 1
+
+	c.mu.Lock()
+	if rank > c.registers[idx] {
+		c.registers[idx] = rank
+	}
+	c.mu.Unlock()
+}
+
+// leadingZeros64 returns number of leading zero bits in x, it returns 64 for x == 0.
+func leadingZeros64(x uint64) uint8 {
+	if x == 0 {
+		return 64
+	}
+	var n uint8
+	for _, shift := range [...]uint8{32, 16, 8, 4, 2, 1} {
+		if x>>(64-shift) == 0 {
+			n += shift
+			x <<= shift
+		}
+	}
+	return n
+}
+
+// mix64 is a finalizer of MurmurHash3 that improves bits distribution of the hash.
+func mix64(x uint64) uint64 {
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb3fe1a85ec53
+	x ^= x >> 33
+	return x
+}
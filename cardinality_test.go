@@ -0,0 +1,86 @@
+package metrics_test
+
+import (
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/admobi/easy-metrics"
+)
+
+func BenchmarkCardinality(b *testing.B) {
+	c := metrics.NewCardinality("unique users", 14)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.AddString("user")
+	}
+}
+
+func assertEstimate(t *testing.T, expected uint64, actual uint64, precision uint8) {
+	// allow 4 standard errors
+	maxErr := 4 * 1.04 / math.Sqrt(float64(uint64(1)<<precision))
+	if math.Abs(float64(actual)-float64(expected)) > maxErr*float64(expected) {
+		t.Errorf("cardinality estimate is out of error range, expected %d, but got %d", expected, actual)
+	}
+}
+
+func TestCardinality(t *testing.T) {
+	c := metrics.NewCardinality("tcardinality", 14)
+	assertCounter(t, 0, c.Get())
+
+	for i := 0; i < 3; i++ {
+		c.AddString("user")
+	}
+	assertCounter(t, 1, c.Get())
+
+	for _, n := range []int{100, 10000, 100000} {
+		c := metrics.NewCardinality("tcardinality", 14)
+		for i := 0; i < n; i++ {
+			c.AddString("user" + strconv.Itoa(i))
+			c.Add([]byte("user" + strconv.Itoa(i)))
+		}
+		assertEstimate(t, uint64(n), c.Estimate(), 14)
+	}
+}
+
+func TestCardinalityMerge(t *testing.T) {
+	c1 := metrics.NewCardinality("c1", 12)
+	c2 := metrics.NewCardinality("c2", 12)
+	for i := 0; i < 10000; i++ {
+		c1.AddString(strconv.Itoa(i))
+		c2.AddString(strconv.Itoa(i + 5000))
+	}
+
+	if err := c1.Merge(c2); err != nil {
+		t.Errorf("unable to merge sketches: %v", err)
+	}
+	assertEstimate(t, 15000, c1.Estimate(), 12)
+
+	err := c1.Merge(metrics.NewCardinality("c3", 10))
+	switch err.(type) {
+	case metrics.ErrPrecisionMismatch:
+	default:
+		t.Errorf("should be precision mismatch error, but got %v", err)
+	}
+}
+
+func TestCardinalitySnapshot(t *testing.T) {
+	rg, _ := metrics.NewTrackRegistry("testCardinalitySnapshot", 10, time.Second, false)
+	c := metrics.NewCardinality("unique", 10)
+	rg.AddMetrics(c)
+
+	for i := 0; i < 100; i++ {
+		c.AddString(strconv.Itoa(i % 10))
+	}
+
+	time.Sleep(time.Second + time.Millisecond*50)
+	assertCounter(t, 0, c.Get())
+
+	m, err := rg.GetSnapshots()[0].GetMetricByName("unique")
+	if err != nil {
+		t.Errorf("error on getting metric: %v", err)
+	}
+	assertCounter(t, 10, m.Get())
+}
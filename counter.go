@@ -1,32 +1,65 @@
 package metrics
 
-import (
-	"strconv"
-	"sync/atomic"
-)
+import "sync/atomic"
 
 // Counter is a cumulative metric that represents a single numerical value that only ever goes up.
 // Satsfies Metric interface.
 type Counter struct {
-	name  string
+	// Value is accessed atomically, it goes first to be 64-bit aligned on 32-bit platforms
 	value uint64
+	name  string
+	described
+	// Sharded counters keep value in shards, see WithShards
+	shards *shards
+	// The latest exemplar since the last snapshot
+	exemplar exemplarSlot
 }
 
 // NewCounter returns new counter that satsfies Metric interface.
-func NewCounter(name string) *Counter {
-	return &Counter{name: name}
+func NewCounter(name string, opts ...Option) *Counter {
+	c := &Counter{name: name, described: newDescribed(KindCounter, opts)}
+	if n := newOptions(KindCounter, opts).shards; n > 0 {
+		c.shards = newShards(n)
+	}
+	return c
 }
 
 // Get returns counter value.
 func (c *Counter) Get() interface{} {
-	return atomic.LoadUint64(&c.value)
+	return c.load()
+}
+
+// Value returns counter value as float64.
+func (c *Counter) Value() float64 {
+	return float64(c.load())
 }
 
 // Add adds delta to counter value.
 func (c *Counter) Add(delta uint64) {
+	if c.shards != nil {
+		c.shards.addUint64(delta)
+		return
+	}
 	atomic.AddUint64(&c.value, delta)
 }
 
+// AddWithExemplar adds delta to counter value and keeps exemplar of the increment with given labels.
+// Only the latest exemplar is kept until the next snapshot.
+//
+//	c.AddWithExemplar(1, map[string]string{"trace_id": traceID})
+func (c *Counter) AddWithExemplar(delta uint64, labels map[string]string) {
+	c.Add(delta)
+	c.exemplar.store(newExemplar(float64(delta), labels))
+}
+
+// Exemplars returns the latest exemplar of counter if it has one.
+func (c *Counter) Exemplars() []Exemplar {
+	if e := c.exemplar.load(); e != nil {
+		return []Exemplar{*e}
+	}
+	return nil
+}
+
 // Inc increases counter value by 1
 func (c *Counter) Inc() {
 	c.Add(1)
@@ -34,7 +67,7 @@ func (c *Counter) Inc() {
 
 // String returns formated representation of counter value.
 func (c *Counter) String() string {
-	return strconv.FormatUint(c.Get().(uint64), 10)
+	return c.formatUint(c.load())
 }
 
 // Name returns metric name.
@@ -42,12 +75,56 @@ func (c *Counter) Name() string {
 	return c.name
 }
 
-// Returns copy of counter. It needs for snapshots.
-func (c *Counter) copy() Metric {
-	return &Counter{value: atomic.LoadUint64(&c.value), name: c.name}
+// Copy returns copy of counter. It needs for snapshots.
+func (c *Counter) Copy() Metric {
+	cp := &Counter{value: c.load(), name: c.name, described: c.described}
+	cp.exemplar.e = c.exemplar.load()
+	return cp
 }
 
-// Flush counter value. It needs for snapshots.
-func (c *Counter) flush() {
+// Reset flushes counter value. It needs for snapshots.
+func (c *Counter) Reset() {
+	if c.shards != nil {
+		c.shards.store(0)
+	}
 	atomic.StoreUint64(&c.value, 0)
+	c.exemplar.store(nil)
+}
+
+// Swap returns copy of counter and flushes its value atomically. It needs for snapshots.
+// Shards of sharded counter are swapped one by one, so every increment gets into exactly one copy.
+func (c *Counter) Swap() Metric {
+	var value uint64
+	if c.shards != nil {
+		value = c.shards.swapUint64()
+	} else {
+		value = atomic.SwapUint64(&c.value, 0)
+	}
+	cp := &Counter{value: value, name: c.name, described: c.described}
+	cp.exemplar.e = c.exemplar.swap()
+	return cp
+}
+
+// Diff returns counter with difference between counter and prev. It needs for cumulative snapshots.
+// If counter is less than prev (e.g. it was reset) the current value is returned.
+func (c *Counter) Diff(prev Metric) Metric {
+	cp := &Counter{value: diffUint64(c.load(), prev), name: c.name, described: c.described}
+	cp.exemplar.e = c.exemplar.load()
+	return cp
+}
+
+// load returns counter value, sharded counters sum their shards.
+func (c *Counter) load() uint64 {
+	if c.shards != nil {
+		return c.shards.loadUint64()
+	}
+	return atomic.LoadUint64(&c.value)
+}
+
+// diffUint64 returns difference between value and value of previous counter.
+func diffUint64(value uint64, prev Metric) uint64 {
+	if pv, ok := prev.Get().(uint64); ok && pv <= value {
+		return value - pv
+	}
+	return value
 }
@@ -41,3 +41,31 @@ type ErrMetricExists string
 func (e ErrMetricExists) Error() string {
 	return "metric with given name exists: " + string(e)
 }
+
+// ErrPrecisionMismatch error type on merging cardinality sketches with different precision.
+type ErrPrecisionMismatch struct{}
+
+func (e ErrPrecisionMismatch) Error() string {
+	return "cardinality precision mismatch"
+}
+
+// ErrLabelCardinality error type on number of label values mismatches number of label names of the metric vector.
+type ErrLabelCardinality string
+
+func (e ErrLabelCardinality) Error() string {
+	return "inconsistent label cardinality for metric " + string(e)
+}
+
+// ErrNegativeDelta error type on adding negative delta to the monotonic counter.
+type ErrNegativeDelta string
+
+func (e ErrNegativeDelta) Error() string {
+	return "negative delta for counter " + string(e)
+}
+
+// ErrStateUnknown error type on setting state that isn't declared in the state set.
+type ErrStateUnknown string
+
+func (e ErrStateUnknown) Error() string {
+	return "undefined state " + string(e)
+}
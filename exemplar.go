@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Exemplar is an example of observation, e.g. trace ID of request which took 2 seconds.
+// It helps to jump from a spike on a chart to a particular request.
+type Exemplar struct {
+	// Labels of exemplar, e.g. {"trace_id": "4bf92f3577b34da6"}.
+	// OpenMetrics limits total length of label names and values to 128 characters.
+	Labels map[string]string
+	// Observed value
+	Value float64
+	// Time of observation
+	Timestamp time.Time
+}
+
+// Exemplarer is implemented by metrics that keep exemplars of observations.
+type Exemplarer interface {
+	// Exemplars returns the latest exemplars since the last snapshot.
+	Exemplars() []Exemplar
+}
+
+// newExemplar returns exemplar of value observed now.
+func newExemplar(value float64, labels map[string]string) *Exemplar {
+	ls := make(map[string]string, len(labels))
+	for k, v := range labels {
+		ls[k] = v
+	}
+	return &Exemplar{Labels: ls, Value: value, Timestamp: time.Now()}
+}
+
+// String returns exemplar in OpenMetrics format, e.g. `{trace_id="4bf92f3577b34da6"} 0.35 1520879607.789`.
+func (e Exemplar) String() string {
+	names := make([]string, 0, len(e.Labels))
+	for n := range e.Labels {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, n := range names {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(n)
+		buf.WriteString(`="`)
+		buf.WriteString(escapeLabelValue(e.Labels[n]))
+		buf.WriteByte('"')
+	}
+	buf.WriteString("} ")
+	buf.WriteString(formatOpenMetricsValue(e.Value))
+	buf.WriteByte(' ')
+	buf.WriteString(strconv.FormatFloat(float64(e.Timestamp.UnixNano())/1e9, 'f', 3, 64))
+	return buf.String()
+}
+
+// exemplarSlot keeps the latest exemplar.
+type exemplarSlot struct {
+	mu sync.Mutex
+	e  *Exemplar
+}
+
+// store replaces exemplar in slot.
+func (s *exemplarSlot) store(e *Exemplar) {
+	s.mu.Lock()
+	s.e = e
+	s.mu.Unlock()
+}
+
+// load returns exemplar in slot or nil if slot is empty.
+func (s *exemplarSlot) load() *Exemplar {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.e
+}
+
+// swap returns exemplar in slot and empties it.
+func (s *exemplarSlot) swap() *Exemplar {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.e
+	s.e = nil
+	return e
+}
+
+// exemplarsOf returns exemplars of metric or nil if metric doesn't keep exemplars.
+func exemplarsOf(m Metric) []Exemplar {
+	if e, ok := m.(Exemplarer); ok {
+		return e.Exemplars()
+	}
+	return nil
+}
+
+// latestExemplar returns the most recent exemplar. Exemplars must not be empty.
+func latestExemplar(exemplars []Exemplar) Exemplar {
+	ret := exemplars[0]
+	for _, e := range exemplars[1:] {
+		if e.Timestamp.After(ret.Timestamp) {
+			ret = e
+		}
+	}
+	return ret
+}
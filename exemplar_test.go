@@ -0,0 +1,63 @@
+package metrics_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/admobi/easy-metrics"
+)
+
+func TestCounterExemplar(t *testing.T) {
+	c := metrics.NewCounter("texemplar_counter")
+	c.Inc()
+	if ex := c.Exemplars(); len(ex) != 0 {
+		t.Errorf("counter without exemplars should have no exemplars, but got %v", ex)
+	}
+
+	labels := map[string]string{"trace_id": "abc"}
+	c.AddWithExemplar(2, labels)
+	labels["trace_id"] = "changed"
+	if v := c.Get().(uint64); v != 3 {
+		t.Errorf("counter value is expected to be 3, but got %d", v)
+	}
+	ex := c.Exemplars()
+	if len(ex) != 1 || ex[0].Labels["trace_id"] != "abc" || ex[0].Value != 2 || ex[0].Timestamp.IsZero() {
+		t.Fatalf("counter should keep exemplar of the last increment, but got %v", ex)
+	}
+	if s := ex[0].String(); !strings.HasPrefix(s, `{trace_id="abc"} 2 `) {
+		t.Errorf("unexpected exemplar string %q", s)
+	}
+
+	cp := c.Swap().(*metrics.Counter)
+	if ex := cp.Exemplars(); len(ex) != 1 {
+		t.Errorf("swapped counter should keep exemplar, but got %v", ex)
+	}
+	if ex := c.Exemplars(); len(ex) != 0 {
+		t.Errorf("exemplar should be flushed by swap, but got %v", ex)
+	}
+}
+
+func TestHistogramExemplars(t *testing.T) {
+	h := metrics.NewHistogram("texemplar_histogram", []float64{1, 5})
+	h.ObserveWithExemplar(0.5, map[string]string{"trace_id": "a"})
+	h.ObserveWithExemplar(0.7, map[string]string{"trace_id": "b"})
+	h.Observe(3)
+	h.ObserveWithExemplar(10, map[string]string{"trace_id": "c"})
+
+	if n := h.Count(); n != 4 {
+		t.Errorf("histogram count is expected to be 4, but got %d", n)
+	}
+	ex := h.Exemplars()
+	if len(ex) != 2 || ex[0].Labels["trace_id"] != "b" || ex[1].Labels["trace_id"] != "c" {
+		t.Fatalf("histogram should keep the latest exemplar of each bucket, but got %v", ex)
+	}
+
+	cp := h.Copy().(*metrics.Histogram)
+	if ex := cp.Exemplars(); len(ex) != 2 {
+		t.Errorf("copy of histogram should keep exemplars, but got %v", ex)
+	}
+	h.Reset()
+	if ex := h.Exemplars(); len(ex) != 0 {
+		t.Errorf("exemplars should be flushed by reset, but got %v", ex)
+	}
+}
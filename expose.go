@@ -3,8 +3,10 @@ package metrics
 import (
 	"fmt"
 	"html/template"
+	"math"
 	"net/http"
 	"sort"
+	"strconv"
 )
 
 func init() {
@@ -47,63 +49,64 @@ func exposeMetrics(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		// FIXME: code for charts output is ugly and needs for refactoring
-		type ChData struct {
-			Index template.JS
-			X     []string
-			Y     []template.JS
+		if qv.Get("format") == "openmetrics" {
+			w.Header().Set("Content-Type", OpenMetricsContentType)
+			WriteOpenMetrics(w, reg)
+			return
 		}
-		type Charts map[template.JS]ChData
+
 		data := struct {
 			Title     string
 			RegName   string
-			Items     map[string]string
-			Charts    Charts
-			Cmt       template.JS
+			Infos     []*Info
+			Items     map[string]metricView
+			Charts    []*chart
 			Snapshots []struct {
 				Ts string
-				M  map[string]string
+				M  map[string]metricView
 			}
 		}{
 			Title:   qv.Get("show") + " :: metrics",
 			RegName: qv.Get("show"),
-			Items:   make(map[string]string, len(reg.GetMetrics())),
-			// Need for silly trick with charts 'data' in template
-			Cmt: "//",
+			Items:   make(map[string]metricView, len(reg.GetMetrics())),
 		}
 
 		t, _ := template.New("registries").Parse(metricsTpl)
 
 		for name, m := range reg.GetMetrics() {
-			data.Items[name] = m.String()
+			if i, ok := m.(*Info); ok {
+				data.Infos = append(data.Infos, i)
+				continue
+			}
+			data.Items[name] = newMetricView(m)
 		}
+		sort.Sort(infosByName(data.Infos))
 
 		switch reg.(type) {
 		case Tracker:
-			charts := Charts{}
+			charts := newChartSet()
 			shs := reg.(Tracker).GetSnapshots()
 			for _, snapshot := range shs {
-				// ms := snapshot.GetMetrics()
-				msData := make(map[string]string)
-				idx := 1
+				ts := snapshot.GetTimestamp().Format("2006-01-02 15:04:05")
+				msData := make(map[string]metricView)
 				for name, metric := range snapshot.GetMetrics() {
-					msData[name] = metric.String()
-					ch := ChData{}
-					ch.Index = template.JS(fmt.Sprintf("trace%d", idx))
-					ch.X = append(charts[template.JS(name)].X, snapshot.GetTimestamp().Format("2006-01-02 15:04:05"))
-					ch.Y = append(charts[template.JS(name)].Y, template.JS(metric.String()))
-					charts[template.JS(name)] = ch
-					idx++
+					if _, ok := metric.(*Info); ok {
+						continue
+					}
+					msData[name] = newMetricView(metric)
+					for name, metric := range chartMetrics(name, metric) {
+						charts.add(name, metric, ts)
+					}
 				}
 				data.Snapshots = append(data.Snapshots, struct {
 					Ts string
-					M  map[string]string
+					M  map[string]metricView
 				}{
-					Ts: snapshot.GetTimestamp().Format("2006-01-02 15:04:05"),
+					Ts: ts,
 					M:  msData,
 				})
 			}
-			data.Charts = charts
+			data.Charts = charts.list()
 
 		default:
 		}
@@ -112,6 +115,357 @@ func exposeMetrics(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// metricView is a representation of metric for templates.
+// Metrics with Rows are shown as a table, others as a single value.
+type metricView struct {
+	Value   string
+	Tooltip string
+	Header  []string
+	Rows    [][]string
+	// Stale metrics are highlighted
+	Stale bool
+}
+
+// newMetricView returns representation of metric for templates.
+func newMetricView(m Metric) metricView {
+	view := metricView{Tooltip: tooltip(metadataOf(m))}
+	if s, ok := m.(interface {
+		Stale() bool
+	}); ok {
+		view.Stale = s.Stale()
+	}
+	switch v := m.(type) {
+	case *TopK:
+		view.Header = []string{"#", "key", "count"}
+		for i, e := range v.Top() {
+			view.Rows = append(view.Rows, []string{strconv.Itoa(i + 1), e.Key, strconv.FormatUint(e.Count, 10)})
+		}
+	case vector:
+		vec := v.vec()
+		view.Header = append(append([]string(nil), vec.labelNames...), "value")
+		for _, ch := range vec.list() {
+			view.Rows = append(view.Rows, append(append([]string(nil), ch.values...), ch.metric.String()))
+		}
+	default:
+		view.Value = m.String()
+	}
+	return view
+}
+
+// tooltip returns text with metric description and unit.
+func tooltip(meta Metadata) string {
+	switch {
+	case meta.Unit == "":
+		return meta.Description
+	case meta.Description == "":
+		return "unit: " + meta.Unit
+	}
+	return meta.Description + " (unit: " + meta.Unit + ")"
+}
+
+// vector is implemented by metric vectors.
+type vector interface {
+	vec() *metricVec
+}
+
+// chartMetrics returns metrics to draw on charts by their names.
+// Vectors are drawn per each child metric.
+func chartMetrics(name string, m Metric) map[string]Metric {
+	if v, ok := m.(vector); ok {
+		ret := make(map[string]Metric)
+		for _, ch := range v.vec().list() {
+			ret[ch.metric.Name()] = ch.metric
+		}
+		return ret
+	}
+	return map[string]Metric{name: m}
+}
+
+// chartValue returns numeric representation of metric for charts.
+// It returns false if metric can't be drawn on a chart.
+func chartValue(m Metric) (float64, bool) {
+	v, ok := ValueOf(m)
+	return v, ok && !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+// Approximate number of ticks on charts axis with formatted values.
+const chartTicks = 5
+
+// chart is a group of traces with the same unit.
+type chart struct {
+	ID     string
+	Unit   string
+	Traces []*trace
+	// Formatter of axis tick labels
+	format Formatter
+}
+
+// Layout returns Plotly layout of chart with axis title and formatted tick labels.
+func (ch *chart) Layout() map[string]interface{} {
+	yaxis := map[string]interface{}{"title": ch.Unit}
+	if ch.format != nil {
+		min, max := math.Inf(1), math.Inf(-1)
+		for _, tr := range ch.Traces {
+			for _, y := range tr.Y {
+				min = math.Min(min, y)
+				max = math.Max(max, y)
+			}
+		}
+		vals := niceTicks(min, max, chartTicks)
+		text := make([]string, len(vals))
+		for i, v := range vals {
+			text[i] = ch.format(v)
+		}
+		yaxis["tickvals"] = vals
+		yaxis["ticktext"] = text
+	}
+	return map[string]interface{}{"yaxis": yaxis}
+}
+
+// trace is a line of a single metric on chart. It's encoded into Plotly trace.
+type trace struct {
+	Name string    `json:"name"`
+	Type string    `json:"type"`
+	X    []string  `json:"x"`
+	Y    []float64 `json:"y"`
+	// Formatted values and exemplars shown on hover
+	Text      []string `json:"text,omitempty"`
+	HoverInfo string   `json:"hoverinfo,omitempty"`
+	Mode      string   `json:"mode,omitempty"`
+	// Points with exemplars are drawn with larger markers
+	Marker *traceMarker `json:"marker,omitempty"`
+	// Band traces are filled areas without legend
+	Line       *traceLine `json:"line,omitempty"`
+	Fill       string     `json:"fill,omitempty"`
+	FillColor  string     `json:"fillcolor,omitempty"`
+	ShowLegend *bool      `json:"showlegend,omitempty"`
+
+	// Lower and upper bounds of band around the trace
+	band *[2]*trace
+}
+
+// Color of bands around chart lines
+const bandColor = "rgba(68, 68, 68, 0.15)"
+
+// traceLine sets line style of trace.
+type traceLine struct {
+	Width float64 `json:"width"`
+}
+
+// newBand returns lower and upper traces of band around trace name.
+// The upper trace is filled down to the lower one, so it must follow the lower one in chart.
+func newBand(name string) *[2]*trace {
+	hidden := false
+	low := &trace{Name: name + " min", Type: "scatter", Line: &traceLine{}, ShowLegend: &hidden}
+	high := &trace{Name: name + " max", Type: "scatter", Line: &traceLine{}, ShowLegend: &hidden,
+		Fill: "tonexty", FillColor: bandColor}
+	return &[2]*trace{low, high}
+}
+
+// Sizes of chart markers
+const (
+	markerSize         = 6
+	exemplarMarkerSize = 12
+)
+
+// traceMarker sets sizes of trace points.
+type traceMarker struct {
+	Size []int `json:"size"`
+}
+
+// setText sets hover text of the last point of trace.
+func (tr *trace) setText(text string) {
+	for len(tr.Text) < len(tr.Y) {
+		tr.Text = append(tr.Text, "")
+	}
+	tr.Text[len(tr.Y)-1] = text
+}
+
+// markExemplar enlarges marker of the last point of trace.
+func (tr *trace) markExemplar() {
+	if tr.Marker == nil {
+		tr.Marker = &traceMarker{}
+		tr.Mode = "lines+markers"
+	}
+	for len(tr.Marker.Size) < len(tr.Y) {
+		tr.Marker.Size = append(tr.Marker.Size, markerSize)
+	}
+	tr.Marker.Size[len(tr.Y)-1] = exemplarMarkerSize
+}
+
+// chartSet builds charts from snapshots.
+type chartSet struct {
+	charts map[string]*chart
+	traces map[string]*trace
+}
+
+func newChartSet() *chartSet {
+	return &chartSet{
+		charts: make(map[string]*chart),
+		traces: make(map[string]*trace),
+	}
+}
+
+// ranger is implemented by metrics that are drawn on charts as a line with a band of values range.
+type ranger interface {
+	chartRange() (low, mid, high float64)
+}
+
+// add adds a point of metric with timestamp ts to the chart of metric unit.
+func (cs *chartSet) add(name string, m Metric, ts string) {
+	var low, high float64
+	y, ok := chartValue(m)
+	r, isRanger := m.(ranger)
+	if isRanger {
+		low, y, high = r.chartRange()
+		ok = true
+	}
+	if !ok {
+		return
+	}
+
+	tr, ok := cs.traces[name]
+	if !ok {
+		unit := metadataOf(m).Unit
+		key := unit
+		if unit == "" && formatterOf(m) != nil {
+			// metrics with own formatters, e.g. ratios or state sets, don't share axis with others
+			key = "\x00" + name
+		}
+		ch, ok := cs.charts[key]
+		if !ok {
+			ch = &chart{Unit: unit}
+			cs.charts[key] = ch
+		}
+		tr = &trace{Name: name, Type: "scatter"}
+		if f := formatterOf(m); f != nil {
+			tr.HoverInfo = "x+text+name"
+			if ch.format == nil {
+				ch.format = f
+			}
+		}
+		if isRanger {
+			tr.band = newBand(name)
+		}
+		ch.Traces = append(ch.Traces, tr)
+		cs.traces[name] = tr
+	}
+	tr.X = append(tr.X, ts)
+	tr.Y = append(tr.Y, y)
+	if tr.band != nil {
+		tr.band[0].X = append(tr.band[0].X, ts)
+		tr.band[0].Y = append(tr.band[0].Y, low)
+		tr.band[1].X = append(tr.band[1].X, ts)
+		tr.band[1].Y = append(tr.band[1].Y, high)
+	}
+	if tr.Marker != nil {
+		tr.Marker.Size = append(tr.Marker.Size, markerSize)
+	}
+
+	var text string
+	if f := formatterOf(m); f != nil {
+		text = f(y)
+	}
+	if ex := exemplarsOf(m); len(ex) > 0 {
+		if text != "" {
+			text += "<br>"
+		}
+		text += "exemplar: " + latestExemplar(ex).String()
+		tr.markExemplar()
+	}
+	if text != "" {
+		tr.setText(text)
+	}
+}
+
+// formatterOf returns formatter of metric or nil if metric has no one.
+func formatterOf(m Metric) Formatter {
+	if d, ok := m.(interface {
+		formatter() Formatter
+	}); ok {
+		return d.formatter()
+	}
+	return nil
+}
+
+// niceTicks returns about n round values of axis ticks that cover range from min to max.
+func niceTicks(min, max float64, n int) []float64 {
+	if math.IsInf(min, 0) || math.IsInf(max, 0) || math.IsNaN(min) || math.IsNaN(max) {
+		return nil
+	}
+	if max <= min {
+		return []float64{min}
+	}
+
+	raw := (max - min) / float64(n)
+	mag := math.Pow(10, math.Floor(math.Log10(raw)))
+	step := 10 * mag
+	for _, m := range []float64{1, 2, 5} {
+		if m*mag >= raw {
+			step = m * mag
+			break
+		}
+	}
+
+	// ticks are counted up front, step may be lost in precision of large values
+	start := math.Floor(min/step) * step
+	count := int(math.Floor((max-start)/step+0.5)) + 1
+	if count > n+2 {
+		count = n + 2
+	}
+	ticks := make([]float64, 0, count)
+	for i := 0; i < count; i++ {
+		ticks = append(ticks, start+float64(i)*step)
+	}
+	return ticks
+}
+
+// list returns charts ordered by unit with traces ordered by name.
+func (cs *chartSet) list() []*chart {
+	ret := make([]*chart, 0, len(cs.charts))
+	for _, ch := range cs.charts {
+		sort.Sort(byName(ch.Traces))
+		// bands go right before their traces
+		traces := make([]*trace, 0, len(ch.Traces))
+		for _, tr := range ch.Traces {
+			if tr.band != nil {
+				traces = append(traces, tr.band[0], tr.band[1])
+			}
+			traces = append(traces, tr)
+		}
+		ch.Traces = traces
+		ret = append(ret, ch)
+	}
+	sort.Sort(byUnit(ret))
+	for i, ch := range ret {
+		ch.ID = fmt.Sprintf("chart%d", i)
+	}
+	return ret
+}
+
+type byName []*trace
+
+func (s byName) Len() int           { return len(s) }
+func (s byName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byName) Less(i, j int) bool { return s[i].Name < s[j].Name }
+
+type infosByName []*Info
+
+func (s infosByName) Len() int           { return len(s) }
+func (s infosByName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s infosByName) Less(i, j int) bool { return s[i].Name() < s[j].Name() }
+
+type byUnit []*chart
+
+func (s byUnit) Len() int      { return len(s) }
+func (s byUnit) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byUnit) Less(i, j int) bool {
+	if s[i].Unit != s[j].Unit {
+		return s[i].Unit < s[j].Unit
+	}
+	return s[i].Traces[0].Name < s[j].Traces[0].Name
+}
+
 // Template for registries list
 const listTpl = `
 <!DOCTYPE html>
@@ -134,16 +488,20 @@ const metricsTpl = `
 	<head>
 		<meta charset="UTF-8">
 		<title>{{.Title}}</title>
-		{{if .Snapshots}}
+		{{if .Charts}}
 		<script src="https://cdn.plot.ly/plotly-1.12.0.min.js"></script>
 		{{end}}
 	</head>
 	<body style="font-family:Arial,Helvetica,sans-serif;font-size:14px;margin:0;padding:0">
-		<h1 style="font-size: 26px;font-weight:500;margin: 0 0 10px 0;padding: 15px 0 10px 20px;text-align: left;position: relative;box-shadow: 0px 3px 19px -9px rgba(0,0,0,.3);z-index: 2;background: #fff">{{.RegName}}</h1>
+		<h1 style="font-size: 26px;font-weight:500;margin: 0 0 10px 0;padding: 15px 0 10px 20px;text-align: left;position: relative;box-shadow: 0px 3px 19px -9px rgba(0,0,0,.3);z-index: 2;background: #fff">{{.RegName}}
+			{{range .Infos}}
+				<div title="{{.Metadata.Description}}" style="font-size:12px;color:#555;margin-top:6px">{{.Name}}:{{$vals := .Values}}{{range .Keys}} <strong>{{.}}</strong>={{index $vals .}}{{end}}</div>
+			{{end}}
+		</h1>
 		<div style="float:left;margin: -10px 0 0 0;padding: 30px 35px 20px 20px;position: relative;z-index: 1;box-shadow: -1px -9px 19px 4px rgba(0,0,0,.15);min-height: 550px;font-family:monospace">
 			<div style="font:18px Arial,Helvetica,sans-serif;margin:10px 0 10px 0;padding: 0;">Current:</div>
 			{{range $key, $val := .Items}}
-				<div>{{ $key }}: {{ $val }}</div>
+				<div title="{{$val.Tooltip}}"{{if $val.Stale}} style="color:#c00"{{end}}>{{ $key }}: {{template "value" $val}}</div>
 			{{else}}
 				<div><strong>no metrics found</strong></div>
 			{{end}}
@@ -155,37 +513,30 @@ const metricsTpl = `
 				<div style="margin-top:10px;font-size:12px">[{{$val.Ts}}]</div>
 				<div>
 					{{range $k, $v := $val.M}}
-						<div>{{ $k }}: {{ $v }}</div>
+						<div title="{{$v.Tooltip}}"{{if $v.Stale}} style="color:#c00"{{end}}>{{ $k }}: {{template "value" $v}}</div>
 					{{end}}
 				</div>
 			{{end}}
 		</div>
-		{{if .Snapshots}}
-			<div id="chartsDiv" style="position: fixed;margin: -60px auto 0 auto;left: 20%;top: 130px;width:70%"></div>
+		{{if .Charts}}
+			<div id="chartsDiv" style="position: fixed;margin: -60px auto 0 auto;left: 20%;top: 130px;bottom: 10px;width:70%;overflow-y:auto">
+				{{range .Charts}}<div id="{{.ID}}"></div>{{end}}
+			</div>
 			<script>
-				{{range $name, $data := .Charts}}
-					var {{$data.Index}} = {
-					x: [
-						{{range $index, $v := $data.X}}
-							{{if $index}},{{end}}{{$v}}
-						{{end}}
-					], 
-					y: [
-						{{range $index, $v := $data.Y}}
-							{{if $index}},{{end}}{{$v}}
-						{{end}}
-					], 
-					type: 'scatter',
-					name: '{{$name}}'
-					};
-				{{end}}
-				var data = [
-					{{.Cmt}}	{{range $index, $v := .Charts}}{{if $index}},{{end}}
-					{{$v.Index}}
+				{{range .Charts}}
+					Plotly.newPlot({{.ID}}, {{.Traces}}, {{.Layout}});
 				{{end}}
-				];
-				Plotly.newPlot('chartsDiv', data);
 			</script>
 		{{end}}
 	</body>
-</html>`
+</html>
+{{define "value"}}
+	{{- if .Header -}}
+		<table style="border-collapse:collapse;margin:2px 0 6px 10px;font-size:12px">
+			<tr>{{range .Header}}<th style="text-align:left;padding:1px 8px;border-bottom:1px solid #ccc">{{.}}</th>{{end}}</tr>
+			{{range .Rows}}<tr>{{range .}}<td style="padding:1px 8px">{{.}}</td>{{end}}</tr>{{else}}<tr><td colspan="{{len .Header}}" style="padding:1px 8px">empty</td></tr>{{end}}
+		</table>
+	{{- else -}}
+		{{.Value}}
+	{{- end -}}
+{{end}}`
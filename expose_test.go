@@ -1,8 +1,10 @@
 package metrics
 
 import (
+	"math"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -14,7 +16,7 @@ func TestExpose(t *testing.T) {
 	}
 
 	c := NewCounter("httpcounter")
-	r.AddMetrics(c)
+	r.AddMetrics(c, NewTopK("httptopk", 3))
 
 	listReq, err := http.NewRequest("GET", "http://example.com/easy-metrics", nil)
 	if err != nil {
@@ -60,3 +62,255 @@ func TestExpose(t *testing.T) {
 		t.Errorf("request error, should be 200 code, but got: %v", w4.Code)
 	}
 }
+
+func TestExposeCharts(t *testing.T) {
+	r, err := NewTrackRegistry("httpchartsreg", 10, time.Hour, false)
+	if err != nil {
+		t.Errorf("unable to create registry: %s", err)
+	}
+
+	c := NewCounter("httpunitcounter", WithUnit("requests"), WithDescription("Number of requests"))
+	c.Add(42)
+	r.AddMetrics(c, NewGauge("httpgauge"), NewTopK("httpcharttopk", 3))
+	r.(*TrackRegistry).makeSnapshot()
+
+	req, err := http.NewRequest("GET", "http://example.com/easy-metrics?show=httpchartsreg", nil)
+	if err != nil {
+		t.Errorf("unable to create request: %s", err)
+	}
+	w := httptest.NewRecorder()
+	exposeMetrics(w, req)
+
+	body := w.Body.String()
+	for _, s := range []string{
+		`title="Number of requests (unit: requests)"`,
+		`"name":"httpunitcounter"`,
+		`"y":[42]`,
+		`{"yaxis":{"title":"requests"}}`,
+	} {
+		if !strings.Contains(body, s) {
+			t.Errorf("charts page should contain %s, got %s", s, body)
+		}
+	}
+}
+
+func TestExposeChartsFormatter(t *testing.T) {
+	r, err := NewTrackRegistry("httpformatreg", 10, time.Hour, false)
+	if err != nil {
+		t.Errorf("unable to create registry: %s", err)
+	}
+
+	g := NewGauge("httpheap", WithUnit("bytes"), WithFormatter(FormatBytesIEC))
+	g.Set(73400320)
+	r.AddMetrics(g)
+	r.(*TrackRegistry).makeSnapshot()
+
+	req, err := http.NewRequest("GET", "http://example.com/easy-metrics?show=httpformatreg", nil)
+	if err != nil {
+		t.Errorf("unable to create request: %s", err)
+	}
+	w := httptest.NewRecorder()
+	exposeMetrics(w, req)
+
+	body := w.Body.String()
+	for _, s := range []string{
+		`"text":["70 MiB"]`,
+		`"tickvals":[73400320]`,
+		`"ticktext":["70 MiB"]`,
+	} {
+		if !strings.Contains(body, s) {
+			t.Errorf("charts page should contain %s, got %s", s, body)
+		}
+	}
+}
+
+func TestExposeExemplars(t *testing.T) {
+	r, err := NewTrackRegistry("httpexemplarreg", 10, time.Hour, false)
+	if err != nil {
+		t.Errorf("unable to create registry: %s", err)
+	}
+
+	c := NewCounter("httpexemplarcounter")
+	c.AddWithExemplar(1, map[string]string{"trace_id": "abc"})
+	r.AddMetrics(c)
+	r.(*TrackRegistry).makeSnapshot()
+	c.Inc()
+	r.(*TrackRegistry).makeSnapshot()
+
+	snapshots := r.GetSnapshots()
+	if ex := snapshots[1].GetExemplars()["httpexemplarcounter"]; len(ex) != 1 {
+		t.Errorf("snapshot should keep exemplar, got %v", ex)
+	}
+	if ex := snapshots[0].GetExemplars(); len(ex) != 0 {
+		t.Errorf("exemplar should be flushed by snapshot, got %v", ex)
+	}
+
+	req, err := http.NewRequest("GET", "http://example.com/easy-metrics?show=httpexemplarreg", nil)
+	if err != nil {
+		t.Errorf("unable to create request: %s", err)
+	}
+	w := httptest.NewRecorder()
+	exposeMetrics(w, req)
+
+	body := w.Body.String()
+	for _, s := range []string{
+		`"mode":"lines+markers"`,
+		`"marker":{"size":[6,12]}`,
+		`exemplar: {trace_id=\"abc\"} 1 `,
+	} {
+		if !strings.Contains(body, s) {
+			t.Errorf("charts page should contain %s, got %s", s, body)
+		}
+	}
+}
+
+func TestExposeOpenMetrics(t *testing.T) {
+	r, err := NewRegistry("httpopenmetricsreg")
+	if err != nil {
+		t.Errorf("unable to create registry: %s", err)
+	}
+	c := NewCounter("httpomcounter")
+	c.Add(5)
+	r.AddMetrics(c)
+
+	req, err := http.NewRequest("GET", "http://example.com/easy-metrics?show=httpopenmetricsreg&format=openmetrics", nil)
+	if err != nil {
+		t.Errorf("unable to create request: %s", err)
+	}
+	w := httptest.NewRecorder()
+	exposeMetrics(w, req)
+
+	if ct := w.Header().Get("Content-Type"); ct != OpenMetricsContentType {
+		t.Errorf("content type should be %s, got %s", OpenMetricsContentType, ct)
+	}
+	if body := w.Body.String(); body != "# TYPE httpomcounter counter\nhttpomcounter_total 5\n# EOF\n" {
+		t.Errorf("unexpected OpenMetrics output %q", body)
+	}
+}
+
+func TestExposeBands(t *testing.T) {
+	r, err := NewTrackRegistry("httpbandreg", 10, time.Hour, false)
+	if err != nil {
+		t.Errorf("unable to create registry: %s", err)
+	}
+
+	g := NewStatsGauge("httpstats")
+	g.Set(2)
+	g.Set(4)
+	r.AddMetrics(g)
+	r.(*TrackRegistry).makeSnapshot()
+
+	req, err := http.NewRequest("GET", "http://example.com/easy-metrics?show=httpbandreg", nil)
+	if err != nil {
+		t.Errorf("unable to create request: %s", err)
+	}
+	w := httptest.NewRecorder()
+	exposeMetrics(w, req)
+
+	body := w.Body.String()
+	low := strings.Index(body, `"name":"httpstats min"`)
+	high := strings.Index(body, `"name":"httpstats max"`)
+	mean := strings.Index(body, `"name":"httpstats","type":"scatter","x":`)
+	if low < 0 || high < low || mean < high {
+		t.Errorf("charts page should contain band traces before the mean trace, got %s", body)
+	}
+	for _, s := range []string{
+		`"y":[2],"line":{"width":0},"showlegend":false`,
+		`"y":[4],"line":{"width":0},"fill":"tonexty"`,
+		`"y":[3]`,
+	} {
+		if !strings.Contains(body, s) {
+			t.Errorf("charts page should contain %s, got %s", s, body)
+		}
+	}
+}
+
+func TestExposeStale(t *testing.T) {
+	r, err := NewRegistry("httpstalereg")
+	if err != nil {
+		t.Errorf("unable to create registry: %s", err)
+	}
+	r.AddMetrics(NewTimestamp("httpstale", time.Minute))
+
+	req, err := http.NewRequest("GET", "http://example.com/easy-metrics?show=httpstalereg", nil)
+	if err != nil {
+		t.Errorf("unable to create request: %s", err)
+	}
+	w := httptest.NewRecorder()
+	exposeMetrics(w, req)
+
+	if body := w.Body.String(); !strings.Contains(body, `<div title="" style="color:#c00">httpstale: never`) {
+		t.Errorf("stale metric should be highlighted, got %s", body)
+	}
+}
+
+func TestExposeInfo(t *testing.T) {
+	r, err := NewTrackRegistry("httpinforeg", 10, time.Hour, false)
+	if err != nil {
+		t.Errorf("unable to create registry: %s", err)
+	}
+	r.AddMetrics(NewInfo("httpbuild", map[string]string{"version": "1.2.0", "commit": "1a2b3c"}))
+	r.(*TrackRegistry).makeSnapshot()
+
+	req, err := http.NewRequest("GET", "http://example.com/easy-metrics?show=httpinforeg", nil)
+	if err != nil {
+		t.Errorf("unable to create request: %s", err)
+	}
+	w := httptest.NewRecorder()
+	exposeMetrics(w, req)
+
+	body := w.Body.String()
+	if !strings.Contains(body, `httpbuild: <strong>commit</strong>=1a2b3c <strong>version</strong>=1.2.0</div>`) {
+		t.Errorf("registry page should contain info header, got %s", body)
+	}
+	if strings.Count(body, "httpbuild") != 1 {
+		t.Errorf("info should be shown only in header, got %s", body)
+	}
+	if ex := r.GetSnapshots()[0].GetMetrics()["httpbuild"]; ex == nil {
+		t.Errorf("info should be kept in snapshots")
+	}
+}
+
+func TestNiceTicks(t *testing.T) {
+	ticks := niceTicks(0, 97, 5)
+	expected := []float64{0, 20, 40, 60, 80, 100}
+	if len(ticks) != len(expected) {
+		t.Fatalf("ticks are expected to be %v, but got %v", expected, ticks)
+	}
+	for i := range ticks {
+		if math.Abs(ticks[i]-expected[i]) > 1e-9 {
+			t.Errorf("ticks are expected to be %v, but got %v", expected, ticks)
+		}
+	}
+
+	// step is below precision of values
+	if ticks := niceTicks(1e16, 1e16+2, 5); len(ticks) == 0 || len(ticks) > 7 {
+		t.Errorf("ticks of large values are expected to be limited, but got %v", ticks)
+	}
+}
+
+func TestExposeTable(t *testing.T) {
+	r, err := NewRegistry("httptablereg")
+	if err != nil {
+		t.Errorf("unable to create registry: %s", err)
+	}
+
+	tk := NewTopK("httptopk", 3)
+	tk.Observe("/index")
+	vec := NewCounterVec("httpvec", "code")
+	vec.WithLabelValues("200").Inc()
+	r.AddMetrics(tk, vec)
+
+	req, err := http.NewRequest("GET", "http://example.com/easy-metrics?show=httptablereg", nil)
+	if err != nil {
+		t.Errorf("unable to create request: %s", err)
+	}
+	w := httptest.NewRecorder()
+	exposeMetrics(w, req)
+	if !strings.Contains(w.Body.String(), "<td style=\"padding:1px 8px\">/index</td>") {
+		t.Errorf("top-k metric should be shown as a table, got %s", w.Body)
+	}
+	if !strings.Contains(w.Body.String(), "<td style=\"padding:1px 8px\">200</td>") {
+		t.Errorf("metric vector should be shown grouped by labels, got %s", w.Body)
+	}
+}
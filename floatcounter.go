@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// FloatCounter is a cumulative metric that represents a single float64 value that only ever goes up,
+// e.g. seconds of CPU time or transferred kilobytes.
+// Satsfies Metric interface.
+type FloatCounter struct {
+	// Float64 bits of value, goes first to be 64-bit aligned for atomic access
+	value uint64
+	name  string
+	described
+}
+
+// NewFloatCounter returns new float counter that satsfies Metric interface.
+func NewFloatCounter(name string, opts ...Option) *FloatCounter {
+	return &FloatCounter{name: name, described: newDescribed(KindCounter, opts)}
+}
+
+// Get returns counter value.
+func (c *FloatCounter) Get() interface{} {
+	return c.Value()
+}
+
+// Value returns counter value.
+func (c *FloatCounter) Value() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&c.value))
+}
+
+// Add adds delta to counter value. It returns ErrNegativeDelta if delta is negative or NaN,
+// counter value isn't changed in this case.
+func (c *FloatCounter) Add(delta float64) error {
+	if delta < 0 || math.IsNaN(delta) {
+		return ErrNegativeDelta(c.name)
+	}
+	for {
+		cur := atomic.LoadUint64(&c.value)
+		nxt := math.Float64bits(math.Float64frombits(cur) + delta)
+		if atomic.CompareAndSwapUint64(&c.value, cur, nxt) {
+			return nil
+		}
+	}
+}
+
+// Inc increases counter value by 1
+func (c *FloatCounter) Inc() {
+	c.Add(1)
+}
+
+// String returns formated representation of counter value.
+func (c *FloatCounter) String() string {
+	return c.formatFloat(c.Value())
+}
+
+// Name returns metric name.
+func (c *FloatCounter) Name() string {
+	return c.name
+}
+
+// Copy returns copy of counter. It needs for snapshots.
+func (c *FloatCounter) Copy() Metric {
+	return &FloatCounter{value: atomic.LoadUint64(&c.value), name: c.name, described: c.described}
+}
+
+// Reset flushes counter value. It needs for snapshots.
+func (c *FloatCounter) Reset() {
+	atomic.StoreUint64(&c.value, 0)
+}
+
+// Swap returns copy of counter and flushes its value atomically. It needs for snapshots.
+func (c *FloatCounter) Swap() Metric {
+	return &FloatCounter{value: atomic.SwapUint64(&c.value, 0), name: c.name, described: c.described}
+}
+
+// Diff returns counter with difference between counter and prev. It needs for cumulative snapshots.
+// If counter is less than prev (e.g. it was reset) the current value is returned.
+func (c *FloatCounter) Diff(prev Metric) Metric {
+	value := c.Value()
+	if pv, ok := prev.Get().(float64); ok && pv <= value {
+		value -= pv
+	}
+	return &FloatCounter{value: math.Float64bits(value), name: c.name, described: c.described}
+}
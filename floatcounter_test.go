@@ -0,0 +1,56 @@
+package metrics_test
+
+import (
+	"testing"
+
+	"github.com/admobi/easy-metrics"
+)
+
+func TestFloatCounter(t *testing.T) {
+	c := metrics.NewFloatCounter("tfloatcounter")
+	if err := c.Add(1.5); err != nil {
+		t.Errorf("unable to add delta: %s", err)
+	}
+	c.Inc()
+	assertGauge(t, 2.5, c.Get())
+
+	err := c.Add(-1)
+	if _, ok := err.(metrics.ErrNegativeDelta); !ok {
+		t.Errorf("negative delta should be rejected with ErrNegativeDelta, but got %v", err)
+	}
+	assertGauge(t, 2.5, c.Get())
+	if s := c.String(); s != "2.5" {
+		t.Errorf("counter string is expected to be 2.5, but got %s", s)
+	}
+
+	cp := c.Swap()
+	assertGauge(t, 2.5, cp.Get())
+	assertGauge(t, 0, c.Get())
+
+	c.Add(4)
+	assertGauge(t, 1.5, c.Diff(cp).Get())
+}
+
+func TestIntGauge(t *testing.T) {
+	g := metrics.NewIntGauge("tintgauge")
+	g.Add(10)
+	g.Inc()
+	g.Sub(3)
+	g.Dec()
+	if v := g.Get().(int64); v != 7 {
+		t.Errorf("gauge value is expected to be 7, but got %d", v)
+	}
+	g.Set(-5)
+	if s := g.String(); s != "-5" {
+		t.Errorf("gauge string is expected to be -5, but got %s", s)
+	}
+	if v, ok := metrics.ValueOf(g); !ok || v != -5 {
+		t.Errorf("gauge numeric value is expected to be -5, but got %v", v)
+	}
+	if p := g.ResetPolicy(); p != metrics.KeepValue {
+		t.Errorf("gauge should keep value on snapshots, but got %s", p)
+	}
+	if v := g.Swap().Get().(int64); v != -5 || g.Get().(int64) != 0 {
+		t.Errorf("swap should return the value and flush gauge, but got %d and %d", v, g.Get())
+	}
+}
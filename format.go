@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+// Formatter formats metric value for humans, e.g. 73400320 as "73.4 MB".
+type Formatter func(v float64) string
+
+var (
+	siBytes  = []string{"B", "kB", "MB", "GB", "TB", "PB", "EB"}
+	iecBytes = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+)
+
+// FormatBytesSI formats number of bytes with SI prefixes, e.g. "73.4 MB".
+func FormatBytesSI(v float64) string {
+	return formatScaled(v, 1000, siBytes)
+}
+
+// FormatBytesIEC formats number of bytes with IEC prefixes, e.g. "70 MiB".
+func FormatBytesIEC(v float64) string {
+	return formatScaled(v, 1024, iecBytes)
+}
+
+// FormatPercent formats fraction as percentage, e.g. 0.1234 as "12.34%".
+func FormatPercent(v float64) string {
+	return trimFloat(v*100, 2) + "%"
+}
+
+// FormatDuration returns formatter of durations measured in given units.
+//
+//	// gauge holds seconds
+//	g := NewGauge("latency", WithFormatter(FormatDuration(time.Second)))
+//	g.Set(0.0123) // shown as "12.3ms"
+func FormatDuration(unit time.Duration) Formatter {
+	return func(v float64) string {
+		return time.Duration(v * float64(unit)).String()
+	}
+}
+
+// FormatFixed returns formatter of values with fixed number of decimals.
+func FormatFixed(precision int) Formatter {
+	return func(v float64) string {
+		return strconv.FormatFloat(v, 'f', precision, 64)
+	}
+}
+
+// formatScaled formats value with the largest unit which keeps value greater or equal to 1.
+func formatScaled(v, base float64, units []string) string {
+	i := 0
+	for math.Abs(v) >= base && i < len(units)-1 {
+		v /= base
+		i++
+	}
+	return trimFloat(v, 2) + " " + units[i]
+}
+
+// trimFloat formats value with up to precision decimals.
+func trimFloat(v float64, precision int) string {
+	p := math.Pow(10, float64(precision))
+	return strconv.FormatFloat(math.Floor(v*p+0.5)/p, 'f', -1, 64)
+}
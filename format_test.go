@@ -0,0 +1,53 @@
+package metrics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/admobi/easy-metrics"
+)
+
+func TestFormatters(t *testing.T) {
+	tests := []struct {
+		f        metrics.Formatter
+		v        float64
+		expected string
+	}{
+		{metrics.FormatBytesSI, 512, "512 B"},
+		{metrics.FormatBytesSI, 73400320, "73.4 MB"},
+		{metrics.FormatBytesIEC, 73400320, "70 MiB"},
+		{metrics.FormatBytesIEC, 1536, "1.5 KiB"},
+		{metrics.FormatPercent, 0.1234, "12.34%"},
+		{metrics.FormatDuration(time.Second), 0.0123, "12.3ms"},
+		{metrics.FormatDuration(time.Millisecond), 1500, "1.5s"},
+		{metrics.FormatFixed(2), 3.14159, "3.14"},
+	}
+	for _, tt := range tests {
+		if s := tt.f(tt.v); s != tt.expected {
+			t.Errorf("formatted value of %v is expected to be %q, but got %q", tt.v, tt.expected, s)
+		}
+	}
+}
+
+func TestMetricFormatter(t *testing.T) {
+	g := metrics.NewGauge("tformat_gauge", metrics.WithFormatter(metrics.FormatBytesIEC))
+	g.Set(73400320)
+	if s := g.String(); s != "70 MiB" {
+		t.Errorf("gauge string is expected to be \"70 MiB\", but got %q", s)
+	}
+	if v := g.Get().(float64); v != 73400320 {
+		t.Errorf("formatter must not change gauge value, but got %v", v)
+	}
+
+	c := metrics.NewCounter("tformat_counter", metrics.WithFormatter(metrics.FormatPercent))
+	c.Inc()
+	if s := c.String(); s != "100%" {
+		t.Errorf("counter string is expected to be \"100%%\", but got %q", s)
+	}
+
+	c = metrics.NewCounter("tformat_plain")
+	c.Add(42)
+	if s := c.String(); s != "42" {
+		t.Errorf("counter string without formatter is expected to be \"42\", but got %q", s)
+	}
+}
@@ -0,0 +1,92 @@
+package metrics
+
+// GaugeFunc is a gauge which value is provided by a function on each read.
+// It's useful to expose values that are owned by someone else, e.g. queue length or pool size.
+// It has no own state, so it's never reset by snapshots.
+// Satsfies Metric interface.
+type GaugeFunc struct {
+	name string
+	described
+	fn func() float64
+}
+
+// NewGaugeFunc returns new gauge that satsfies Metric interface.
+// Function fn is called on each read of gauge value and must be safe for concurrent use.
+func NewGaugeFunc(name string, fn func() float64, opts ...Option) *GaugeFunc {
+	return &GaugeFunc{name: name, described: newDescribed(KindGauge, opts), fn: fn}
+}
+
+// Get returns gauge value.
+func (g *GaugeFunc) Get() interface{} {
+	return g.fn()
+}
+
+// Value returns gauge value.
+func (g *GaugeFunc) Value() float64 {
+	return g.fn()
+}
+
+// String returns formated representation of gauge value.
+func (g *GaugeFunc) String() string {
+	return g.formatFloat(g.fn())
+}
+
+// Name returns metric name.
+func (g *GaugeFunc) Name() string {
+	return g.name
+}
+
+// Copy returns copy of gauge with the current value. It needs for snapshots.
+func (g *GaugeFunc) Copy() Metric {
+	v := g.fn()
+	return &GaugeFunc{name: g.name, described: g.described, fn: func() float64 { return v }}
+}
+
+// CounterFunc is a counter which value is provided by a function on each read.
+// The function must return a value that only ever goes up.
+// It has no own state, so it's never reset by snapshots.
+// Satsfies Metric interface.
+type CounterFunc struct {
+	name string
+	described
+	fn func() uint64
+}
+
+// NewCounterFunc returns new counter that satsfies Metric interface.
+// Function fn is called on each read of counter value and must be safe for concurrent use.
+func NewCounterFunc(name string, fn func() uint64, opts ...Option) *CounterFunc {
+	return &CounterFunc{name: name, described: newDescribed(KindCounter, opts), fn: fn}
+}
+
+// Get returns counter value.
+func (c *CounterFunc) Get() interface{} {
+	return c.fn()
+}
+
+// Value returns counter value as float64.
+func (c *CounterFunc) Value() float64 {
+	return float64(c.fn())
+}
+
+// String returns formated representation of counter value.
+func (c *CounterFunc) String() string {
+	return c.formatUint(c.fn())
+}
+
+// Name returns metric name.
+func (c *CounterFunc) Name() string {
+	return c.name
+}
+
+// Copy returns copy of counter with the current value. It needs for snapshots.
+func (c *CounterFunc) Copy() Metric {
+	v := c.fn()
+	return &CounterFunc{name: c.name, described: c.described, fn: func() uint64 { return v }}
+}
+
+// Diff returns counter with difference between counter and prev. It needs for cumulative snapshots.
+// If counter is less than prev the current value is returned.
+func (c *CounterFunc) Diff(prev Metric) Metric {
+	v := diffUint64(c.fn(), prev)
+	return &CounterFunc{name: c.name, described: c.described, fn: func() uint64 { return v }}
+}
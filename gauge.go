@@ -2,29 +2,45 @@ package metrics
 
 import (
 	"math"
-	"strconv"
 	"sync/atomic"
 )
 
 // Gauge is a metric that represents a single float64 value that can arbitrarily go up and down.
 // Satsfies Metric interface.
 type Gauge struct {
-	name  string
+	// Float64 bits of value, goes first to be 64-bit aligned for atomic access
 	value uint64
+	name  string
+	described
+	// Sharded gauges keep value in shards, see WithShards
+	shards *shards
 }
 
 // NewGauge returns new gauge metric that satsfies Metric interface.
-func NewGauge(name string) *Gauge {
-	return &Gauge{name: name}
+func NewGauge(name string, opts ...Option) *Gauge {
+	g := &Gauge{name: name, described: newDescribed(KindGauge, opts)}
+	if n := newOptions(KindGauge, opts).shards; n > 0 {
+		g.shards = newShards(n)
+	}
+	return g
 }
 
 // Get returns gauge value.
 func (g *Gauge) Get() interface{} {
-	return math.Float64frombits(atomic.LoadUint64(&g.value))
+	return g.load()
+}
+
+// Value returns gauge value.
+func (g *Gauge) Value() float64 {
+	return g.load()
 }
 
 // Add adds delta to gauge value.
 func (g *Gauge) Add(delta float64) {
+	if g.shards != nil {
+		g.shards.addFloat64(delta)
+		return
+	}
 	for {
 		cur := atomic.LoadUint64(&g.value)
 		curVal := math.Float64frombits(cur)
@@ -37,7 +53,12 @@ func (g *Gauge) Add(delta float64) {
 }
 
 // Set sets gauge value to value.
+// Set of sharded gauge isn't atomic with concurrent Add, it's designed for gauges that are mostly added.
 func (g *Gauge) Set(value float64) {
+	if g.shards != nil {
+		g.shards.store(math.Float64bits(value))
+		return
+	}
 	atomic.StoreUint64(&g.value, math.Float64bits(value))
 }
 
@@ -48,7 +69,7 @@ func (g *Gauge) Sub(delta float64) {
 
 // String returns formated representation of gauge value.
 func (g *Gauge) String() string {
-	return strconv.FormatFloat(g.Get().(float64), 'g', -1, 64)
+	return g.formatFloat(g.Value())
 }
 
 // Name returns metric name.
@@ -56,12 +77,36 @@ func (g *Gauge) Name() string {
 	return g.name
 }
 
-// Returns copy of gauge. It needs for snapshots.
-func (g *Gauge) copy() Metric {
-	return &Gauge{value: atomic.LoadUint64(&g.value), name: g.name}
+// Copy returns copy of gauge. It needs for snapshots.
+func (g *Gauge) Copy() Metric {
+	return &Gauge{value: math.Float64bits(g.load()), name: g.name, described: g.described}
 }
 
-// Flush gauge value. It needs for snapshots.
-func (g *Gauge) flush() {
+// Reset flushes gauge value. It needs for snapshots.
+func (g *Gauge) Reset() {
+	if g.shards != nil {
+		g.shards.store(0)
+	}
 	atomic.StoreUint64(&g.value, 0)
 }
+
+// Swap returns copy of gauge and flushes its value atomically. It needs for snapshots.
+func (g *Gauge) Swap() Metric {
+	if g.shards != nil {
+		return &Gauge{value: math.Float64bits(g.shards.swapFloat64()), name: g.name, described: g.described}
+	}
+	return &Gauge{value: atomic.SwapUint64(&g.value, 0), name: g.name, described: g.described}
+}
+
+// ResetPolicy returns KeepValue, gauge holds the current value and isn't flushed by snapshots.
+func (g *Gauge) ResetPolicy() ResetPolicy {
+	return KeepValue
+}
+
+// load returns gauge value, sharded gauges sum their shards.
+func (g *Gauge) load() float64 {
+	if g.shards != nil {
+		return g.shards.loadFloat64()
+	}
+	return math.Float64frombits(atomic.LoadUint64(&g.value))
+}
@@ -0,0 +1,279 @@
+package metrics
+
+import (
+	"bytes"
+	"math"
+	"sort"
+	"strconv"
+	"sync/atomic"
+)
+
+// Histogram is a metric that samples observations and counts them in configurable buckets.
+// It also keeps the total count and the sum of observed values.
+// Satsfies Metric interface.
+type Histogram struct {
+	// Total count and float64 bits of sum are accessed atomically,
+	// so they go first to be 64-bit aligned on 32-bit platforms.
+	count uint64
+	sum   uint64
+	name  string
+	described
+	// Sorted upper bounds of buckets. The last implicit bucket is +Inf.
+	bounds []float64
+	counts []uint64
+	// The latest exemplars of buckets since the last snapshot
+	exemplars []exemplarSlot
+}
+
+// Bucket is a histogram bucket with count of observations that are less or equal to UpperBound
+// and greater than upper bound of the previous bucket.
+type Bucket struct {
+	UpperBound float64
+	Count      uint64
+}
+
+// NewHistogram returns new histogram that satsfies Metric interface.
+// Buckets are upper bounds of histogram buckets, they will be sorted.
+// The +Inf bucket is always added implicitly.
+func NewHistogram(name string, buckets []float64, opts ...Option) *Histogram {
+	bounds := make([]float64, 0, len(buckets))
+	for _, b := range buckets {
+		if !math.IsInf(b, 1) && !math.IsNaN(b) {
+			bounds = append(bounds, b)
+		}
+	}
+	sort.Float64s(bounds)
+
+	// remove duplicated bounds
+	uniq := bounds[:0]
+	for i, b := range bounds {
+		if i == 0 || b != bounds[i-1] {
+			uniq = append(uniq, b)
+		}
+	}
+
+	return &Histogram{
+		name:      name,
+		described: newDescribed(KindHistogram, opts),
+		bounds:    uniq,
+		counts:    make([]uint64, len(uniq)+1),
+		exemplars: make([]exemplarSlot, len(uniq)+1),
+	}
+}
+
+// LinearBuckets returns count buckets, each width wide, where the lowest bucket has an upper bound of start.
+// It returns nil if count is less than 1.
+func LinearBuckets(start, width float64, count int) []float64 {
+	if count < 1 {
+		return nil
+	}
+	buckets := make([]float64, count)
+	for i := range buckets {
+		buckets[i] = start + float64(i)*width
+	}
+	return buckets
+}
+
+// ExponentialBuckets returns count buckets, where the lowest bucket has an upper bound of start
+// and each following bucket's upper bound is factor times the previous one.
+// It returns nil if count is less than 1, start is not positive or factor is not greater than 1.
+func ExponentialBuckets(start, factor float64, count int) []float64 {
+	if count < 1 || start <= 0 || factor <= 1 {
+		return nil
+	}
+	buckets := make([]float64, count)
+	for i := range buckets {
+		buckets[i] = start
+		start *= factor
+	}
+	return buckets
+}
+
+// Observe adds a single observation into histogram.
+func (h *Histogram) Observe(value float64) {
+	h.observe(sort.SearchFloat64s(h.bounds, value), value)
+}
+
+// ObserveWithExemplar adds a single observation into histogram and keeps its exemplar with given labels.
+// Only the latest exemplar of each bucket is kept until the next snapshot.
+//
+//	h.ObserveWithExemplar(time.Since(start).Seconds(), map[string]string{"trace_id": traceID})
+func (h *Histogram) ObserveWithExemplar(value float64, labels map[string]string) {
+	idx := sort.SearchFloat64s(h.bounds, value)
+	h.observe(idx, value)
+	h.exemplars[idx].store(newExemplar(value, labels))
+}
+
+// observe adds observation into bucket idx.
+func (h *Histogram) observe(idx int, value float64) {
+	atomic.AddUint64(&h.counts[idx], 1)
+	atomic.AddUint64(&h.count, 1)
+	for {
+		cur := atomic.LoadUint64(&h.sum)
+		nxt := math.Float64bits(math.Float64frombits(cur) + value)
+		if atomic.CompareAndSwapUint64(&h.sum, cur, nxt) {
+			return
+		}
+	}
+}
+
+// Exemplars returns the latest exemplars of buckets in order of buckets. Buckets without exemplars are skipped.
+func (h *Histogram) Exemplars() []Exemplar {
+	var ret []Exemplar
+	for i := range h.exemplars {
+		if e := h.exemplars[i].load(); e != nil {
+			ret = append(ret, *e)
+		}
+	}
+	return ret
+}
+
+// Get returns histogram buckets.
+func (h *Histogram) Get() interface{} {
+	return h.Buckets()
+}
+
+// Buckets returns histogram buckets with counts of observations.
+// The last bucket has +Inf upper bound.
+func (h *Histogram) Buckets() []Bucket {
+	buckets := make([]Bucket, len(h.counts))
+	for i := range h.counts {
+		buckets[i].Count = atomic.LoadUint64(&h.counts[i])
+		if i < len(h.bounds) {
+			buckets[i].UpperBound = h.bounds[i]
+		} else {
+			buckets[i].UpperBound = math.Inf(1)
+		}
+	}
+	return buckets
+}
+
+// Value returns total count of observations as float64.
+func (h *Histogram) Value() float64 {
+	return float64(h.Count())
+}
+
+// Sample returns count, sum and bucket counts of histogram.
+// Bucket counts are named by their upper bounds, e.g. "le_0.5" or "le_+Inf".
+func (h *Histogram) Sample() Sample {
+	s := Sample{
+		Name:   h.name,
+		Kind:   h.meta.Kind,
+		Value:  float64(h.Count()),
+		Fields: map[string]float64{"sum": h.Sum()},
+	}
+	s.Fields["count"] = s.Value
+	for _, b := range h.Buckets() {
+		s.Fields["le_"+strconv.FormatFloat(b.UpperBound, 'g', -1, 64)] = float64(b.Count)
+	}
+	return s
+}
+
+// Count returns total count of observations.
+func (h *Histogram) Count() uint64 {
+	return atomic.LoadUint64(&h.count)
+}
+
+// Sum returns sum of observed values.
+func (h *Histogram) Sum() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&h.sum))
+}
+
+// String returns formated representation of histogram: count, sum and bucket counts.
+func (h *Histogram) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("count=")
+	buf.WriteString(strconv.FormatUint(h.Count(), 10))
+	buf.WriteString(" sum=")
+	buf.WriteString(h.formatFloat(h.Sum()))
+	buf.WriteString(" buckets=[")
+	for i, b := range h.Buckets() {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		if math.IsInf(b.UpperBound, 1) {
+			buf.WriteString("+Inf")
+		} else {
+			buf.WriteString(h.formatFloat(b.UpperBound))
+		}
+		buf.WriteByte(':')
+		buf.WriteString(strconv.FormatUint(b.Count, 10))
+	}
+	buf.WriteByte(']')
+	return buf.String()
+}
+
+// Name returns metric name.
+func (h *Histogram) Name() string {
+	return h.name
+}
+
+// Copy returns copy of histogram. It needs for snapshots.
+func (h *Histogram) Copy() Metric {
+	cp := &Histogram{
+		name:      h.name,
+		described: h.described,
+		bounds:    h.bounds,
+		counts:    make([]uint64, len(h.counts)),
+		exemplars: make([]exemplarSlot, len(h.counts)),
+		count:     atomic.LoadUint64(&h.count),
+		sum:       atomic.LoadUint64(&h.sum),
+	}
+	for i := range h.counts {
+		cp.counts[i] = atomic.LoadUint64(&h.counts[i])
+		cp.exemplars[i].e = h.exemplars[i].load()
+	}
+	return cp
+}
+
+// Reset flushes histogram values. It needs for snapshots.
+func (h *Histogram) Reset() {
+	for i := range h.counts {
+		atomic.StoreUint64(&h.counts[i], 0)
+		h.exemplars[i].store(nil)
+	}
+	atomic.StoreUint64(&h.count, 0)
+	atomic.StoreUint64(&h.sum, 0)
+}
+
+// Swap returns copy of histogram and flushes its values. It needs for snapshots.
+// Each value is swapped atomically, so every observation gets into exactly one copy,
+// but an observation made during swap may be counted in buckets and total count of different copies.
+func (h *Histogram) Swap() Metric {
+	cp := &Histogram{
+		name:      h.name,
+		described: h.described,
+		bounds:    h.bounds,
+		counts:    make([]uint64, len(h.counts)),
+		exemplars: make([]exemplarSlot, len(h.counts)),
+		count:     atomic.SwapUint64(&h.count, 0),
+		sum:       atomic.SwapUint64(&h.sum, 0),
+	}
+	for i := range h.counts {
+		cp.counts[i] = atomic.SwapUint64(&h.counts[i], 0)
+		cp.exemplars[i].e = h.exemplars[i].swap()
+	}
+	return cp
+}
+
+// Diff returns histogram with difference between histogram and prev. It needs for cumulative snapshots.
+// If any count of histogram is less than prev (e.g. it was reset) the current values are returned.
+func (h *Histogram) Diff(prev Metric) Metric {
+	cp := h.Copy().(*Histogram)
+	p, ok := prev.(*Histogram)
+	if !ok || len(p.counts) != len(cp.counts) || p.count > cp.count {
+		return cp
+	}
+	for i := range cp.counts {
+		if p.counts[i] > cp.counts[i] {
+			return cp
+		}
+	}
+
+	for i := range cp.counts {
+		cp.counts[i] -= p.counts[i]
+	}
+	cp.count -= p.count
+	cp.sum = math.Float64bits(math.Float64frombits(cp.sum) - math.Float64frombits(p.sum))
+	return cp
+}
@@ -0,0 +1,84 @@
+package metrics_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/admobi/easy-metrics"
+)
+
+func BenchmarkHistogram(b *testing.B) {
+	h := metrics.NewHistogram("latency", metrics.ExponentialBuckets(1, 2, 10))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h.Observe(float64(i % 1024))
+	}
+}
+
+func TestBucketsHelpers(t *testing.T) {
+	lb := metrics.LinearBuckets(1, 2, 4)
+	if !reflect.DeepEqual(lb, []float64{1, 3, 5, 7}) {
+		t.Errorf("linear buckets mismatch, got %v", lb)
+	}
+
+	eb := metrics.ExponentialBuckets(1, 10, 3)
+	if !reflect.DeepEqual(eb, []float64{1, 10, 100}) {
+		t.Errorf("exponential buckets mismatch, got %v", eb)
+	}
+
+	if metrics.LinearBuckets(1, 1, 0) != nil {
+		t.Error("linear buckets with zero count should be nil")
+	}
+	if metrics.ExponentialBuckets(0, 2, 3) != nil {
+		t.Error("exponential buckets with zero start should be nil")
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	h := metrics.NewHistogram("thistogram", []float64{10, 1, 5, 5})
+
+	for _, v := range []float64{0.5, 1, 2, 5, 7, 100} {
+		h.Observe(v)
+	}
+
+	assertCounter(t, 6, h.Count())
+	assertGauge(t, 115.5, h.Sum())
+
+	expected := []metrics.Bucket{
+		{UpperBound: 1, Count: 2},
+		{UpperBound: 5, Count: 2},
+		{UpperBound: 10, Count: 1},
+		{UpperBound: math.Inf(1), Count: 1},
+	}
+	if !reflect.DeepEqual(expected, h.Get()) {
+		t.Errorf("buckets mismatch, expected %v, but got %v", expected, h.Get())
+	}
+
+	if s := h.String(); s != "count=6 sum=115.5 buckets=[1:2 5:2 10:1 +Inf:1]" {
+		t.Errorf("unexpected string representation: %s", s)
+	}
+}
+
+func TestHistogramSnapshot(t *testing.T) {
+	rg, _ := metrics.NewTrackRegistry("testHistogramSnapshot", 10, time.Second, false)
+	h := metrics.NewHistogram("histogram", metrics.LinearBuckets(1, 1, 3))
+	rg.AddMetrics(h)
+
+	for i := 0; i < 100; i++ {
+		h.Observe(float64(i%4) + 0.5)
+	}
+
+	time.Sleep(time.Second + time.Millisecond*50)
+	assertCounter(t, 0, h.Count())
+
+	m, err := rg.GetSnapshots()[0].GetMetricByName("histogram")
+	if err != nil {
+		t.Errorf("error on getting metric: %v", err)
+	}
+	for _, b := range m.Get().([]metrics.Bucket) {
+		assertCounter(t, 25, b.Count)
+	}
+}
@@ -0,0 +1,192 @@
+package metrics
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// InFlight is a metric that tracks number of operations in progress, e.g. concurrent requests.
+// Besides the current value it keeps the maximum and the time-weighted mean value reached
+// since the last snapshot, so short bursts between snapshots are visible.
+// Snapshots restart the window of maximum and mean, the current value is kept.
+// Satsfies Metric interface.
+type InFlight struct {
+	name string
+	described
+
+	mu      sync.Mutex
+	current int64
+	max     int64
+	// Integral of current value over time since start of window, in seconds
+	area float64
+	// Start of window and time of the last change of current value
+	start time.Time
+	last  time.Time
+	// Frozen metrics are snapshot copies which values don't change over time.
+	frozen   bool
+	frozenAt time.Time
+}
+
+// NewInFlight returns new in-flight metric that satsfies Metric interface.
+func NewInFlight(name string, opts ...Option) *InFlight {
+	now := time.Now()
+	return &InFlight{name: name, described: newDescribed(KindGauge, opts), start: now, last: now}
+}
+
+// Enter marks start of an operation.
+func (f *InFlight) Enter() {
+	f.add(1)
+}
+
+// Leave marks end of an operation.
+func (f *InFlight) Leave() {
+	f.add(-1)
+}
+
+// Track tracks execution of function fn.
+//
+//	inFlight.Track(func() { handle(req) })
+func (f *InFlight) Track(fn func()) {
+	f.Enter()
+	defer f.Leave()
+	fn()
+}
+
+// Get returns the current number of operations in progress.
+func (f *InFlight) Get() interface{} {
+	return f.Current()
+}
+
+// Value returns the current number of operations in progress as float64.
+func (f *InFlight) Value() float64 {
+	return float64(f.Current())
+}
+
+// Sample returns the current, maximum and mean number of operations in progress.
+func (f *InFlight) Sample() Sample {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return Sample{
+		Name:  f.name,
+		Kind:  f.meta.Kind,
+		Value: float64(f.current),
+		Fields: map[string]float64{
+			"current": float64(f.current),
+			"max":     float64(f.max),
+			"mean":    f.mean(),
+		},
+	}
+}
+
+// Current returns the current number of operations in progress.
+func (f *InFlight) Current() int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.current
+}
+
+// Max returns the maximum number of operations in progress since the last snapshot.
+func (f *InFlight) Max() int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.max
+}
+
+// Mean returns the time-weighted mean number of operations in progress since the last snapshot.
+func (f *InFlight) Mean() float64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.mean()
+}
+
+// String returns formated representation of metric, e.g. "current=3 max=10 mean=2.5".
+func (f *InFlight) String() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return "current=" + f.formatFloat(float64(f.current)) +
+		" max=" + f.formatFloat(float64(f.max)) +
+		" mean=" + strconv.FormatFloat(f.mean(), 'f', 2, 64)
+}
+
+// Name returns metric name.
+func (f *InFlight) Name() string {
+	return f.name
+}
+
+// Copy returns copy of metric. It needs for snapshots.
+func (f *InFlight) Copy() Metric {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.freeze(time.Now())
+}
+
+// Reset restarts window of maximum and mean values, the current value is kept. It needs for snapshots.
+func (f *InFlight) Reset() {
+	f.mu.Lock()
+	f.restart(time.Now())
+	f.mu.Unlock()
+}
+
+// Swap returns copy of metric and restarts window of maximum and mean values atomically. It needs for snapshots.
+func (f *InFlight) Swap() Metric {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := time.Now()
+	cp := f.freeze(now)
+	f.restart(now)
+	return cp
+}
+
+func (f *InFlight) add(delta int64) {
+	f.mu.Lock()
+	f.advance(time.Now())
+	f.current += delta
+	if f.current > f.max {
+		f.max = f.current
+	}
+	f.mu.Unlock()
+}
+
+// advance accumulates area of the current value up to now.
+func (f *InFlight) advance(now time.Time) {
+	f.area += float64(f.current) * now.Sub(f.last).Seconds()
+	f.last = now
+}
+
+// restart starts new window at now.
+func (f *InFlight) restart(now time.Time) {
+	f.max = f.current
+	f.area = 0
+	f.start = now
+	f.last = now
+}
+
+// freeze returns frozen copy of metric at now.
+func (f *InFlight) freeze(now time.Time) *InFlight {
+	f.advance(now)
+	return &InFlight{
+		name:      f.name,
+		described: f.described,
+		current:   f.current,
+		max:       f.max,
+		area:      f.area,
+		start:     f.start,
+		last:      now,
+		frozen:    true,
+		frozenAt:  now,
+	}
+}
+
+func (f *InFlight) mean() float64 {
+	end := time.Now()
+	if f.frozen {
+		end = f.frozenAt
+	}
+	elapsed := end.Sub(f.start).Seconds()
+	if elapsed <= 0 {
+		return float64(f.current)
+	}
+	area := f.area + float64(f.current)*end.Sub(f.last).Seconds()
+	return area / elapsed
+}
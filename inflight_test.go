@@ -0,0 +1,59 @@
+package metrics_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/admobi/easy-metrics"
+)
+
+func TestInFlight(t *testing.T) {
+	f := metrics.NewInFlight("tinflight")
+
+	var wg sync.WaitGroup
+	release := make(chan struct{})
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f.Track(func() { <-release })
+		}()
+	}
+	for f.Current() != 5 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	f.Enter()
+	if v := f.Get().(int64); v != 1 {
+		t.Errorf("current value is expected to be 1, but got %d", v)
+	}
+	if m := f.Max(); m != 5 {
+		t.Errorf("max value is expected to be 5, but got %d", m)
+	}
+	if m := f.Mean(); m <= 0 || m > 5 {
+		t.Errorf("mean value is expected to be in range (0, 5], but got %v", m)
+	}
+
+	cp := f.Swap().(*metrics.InFlight)
+	if m := cp.Max(); m != 5 {
+		t.Errorf("max value of swapped metric is expected to be 5, but got %d", m)
+	}
+	mean := cp.Mean()
+	time.Sleep(10 * time.Millisecond)
+	if m := cp.Mean(); m != mean {
+		t.Errorf("mean value of swapped metric must not change, but got %v and %v", mean, m)
+	}
+
+	// window is restarted, the current value is kept
+	if v, m := f.Current(), f.Max(); v != 1 || m != 1 {
+		t.Errorf("current and max values are expected to be 1 after swap, but got %d and %d", v, m)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if m := f.Mean(); m != 1 {
+		t.Errorf("mean value of constant load is expected to be 1, but got %v", m)
+	}
+	f.Leave()
+}
@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// Info is a metric that holds textual information as key/value pairs, e.g. version or git commit of build.
+// It's never flushed by snapshots and is shown as a header block on the registry page.
+// Satsfies Metric interface.
+type Info struct {
+	name string
+	described
+
+	mu     sync.RWMutex
+	values map[string]string
+}
+
+// NewInfo returns new info metric with given values that satsfies Metric interface.
+//
+//	i := NewInfo("build", map[string]string{"version": "1.2.0"})
+func NewInfo(name string, values map[string]string, opts ...Option) *Info {
+	i := &Info{name: name, described: newDescribed(KindInfo, opts), values: make(map[string]string, len(values))}
+	for k, v := range values {
+		i.values[k] = v
+	}
+	return i
+}
+
+// Set sets value of key.
+func (i *Info) Set(key, value string) {
+	i.mu.Lock()
+	i.values[key] = value
+	i.mu.Unlock()
+}
+
+// Get returns copy of values by keys.
+func (i *Info) Get() interface{} {
+	return i.Values()
+}
+
+// Values returns copy of values by keys.
+func (i *Info) Values() map[string]string {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	ret := make(map[string]string, len(i.values))
+	for k, v := range i.values {
+		ret[k] = v
+	}
+	return ret
+}
+
+// Keys returns sorted keys of values.
+func (i *Info) Keys() []string {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	keys := make([]string, 0, len(i.values))
+	for k := range i.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// String returns values sorted by keys, e.g. `commit="1a2b3c" version="1.2.0"`.
+func (i *Info) String() string {
+	values := i.Values()
+	var buf bytes.Buffer
+	for n, k := range i.Keys() {
+		if n > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(k)
+		buf.WriteByte('=')
+		buf.WriteString(strconv.Quote(values[k]))
+	}
+	return buf.String()
+}
+
+// Name returns metric name.
+func (i *Info) Name() string {
+	return i.name
+}
+
+// Copy returns copy of info metric. It needs for snapshots.
+func (i *Info) Copy() Metric {
+	return &Info{name: i.name, described: i.described, values: i.Values()}
+}
+
+// ResetPolicy returns KeepValue, info metrics are never flushed by snapshots.
+func (i *Info) ResetPolicy() ResetPolicy {
+	return KeepValue
+}
@@ -0,0 +1,33 @@
+package metrics_test
+
+import (
+	"testing"
+
+	"github.com/admobi/easy-metrics"
+)
+
+func TestInfo(t *testing.T) {
+	values := map[string]string{"version": "1.2.0"}
+	i := metrics.NewInfo("tinfo", values)
+	values["version"] = "changed"
+	i.Set("commit", "1a2b3c")
+
+	if v := i.Get().(map[string]string); v["version"] != "1.2.0" || v["commit"] != "1a2b3c" {
+		t.Errorf("unexpected info values %v", v)
+	}
+	if s := i.String(); s != `commit="1a2b3c" version="1.2.0"` {
+		t.Errorf("unexpected info string %s", s)
+	}
+	if p := i.ResetPolicy(); p != metrics.KeepValue {
+		t.Errorf("info should never be flushed, but got policy %s", p)
+	}
+	if _, ok := metrics.ValueOf(i); ok {
+		t.Errorf("info should have no numeric value")
+	}
+
+	cp := i.Copy().(*metrics.Info)
+	i.Set("commit", "4d5e6f")
+	if v := cp.Values()["commit"]; v != "1a2b3c" {
+		t.Errorf("copy of info must not change, but got commit %s", v)
+	}
+}
@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"strconv"
+	"sync/atomic"
+)
+
+// IntGauge is a metric that represents a single int64 value that can arbitrarily go up and down,
+// e.g. number of open connections. It's updated with plain atomic addition.
+// Satsfies Metric interface.
+type IntGauge struct {
+	// Value is accessed atomically, it goes first to be 64-bit aligned on 32-bit platforms
+	value int64
+	name  string
+	described
+}
+
+// NewIntGauge returns new integer gauge that satsfies Metric interface.
+func NewIntGauge(name string, opts ...Option) *IntGauge {
+	return &IntGauge{name: name, described: newDescribed(KindGauge, opts)}
+}
+
+// Get returns gauge value.
+func (g *IntGauge) Get() interface{} {
+	return atomic.LoadInt64(&g.value)
+}
+
+// Value returns gauge value as float64.
+func (g *IntGauge) Value() float64 {
+	return float64(atomic.LoadInt64(&g.value))
+}
+
+// Add adds delta to gauge value.
+func (g *IntGauge) Add(delta int64) {
+	atomic.AddInt64(&g.value, delta)
+}
+
+// Sub substarcts delta from gauge value.
+func (g *IntGauge) Sub(delta int64) {
+	atomic.AddInt64(&g.value, -delta)
+}
+
+// Inc increases gauge value by 1
+func (g *IntGauge) Inc() {
+	atomic.AddInt64(&g.value, 1)
+}
+
+// Dec decreases gauge value by 1
+func (g *IntGauge) Dec() {
+	atomic.AddInt64(&g.value, -1)
+}
+
+// Set sets gauge value to value.
+func (g *IntGauge) Set(value int64) {
+	atomic.StoreInt64(&g.value, value)
+}
+
+// String returns formated representation of gauge value.
+func (g *IntGauge) String() string {
+	v := atomic.LoadInt64(&g.value)
+	if g.format != nil {
+		return g.format(float64(v))
+	}
+	return strconv.FormatInt(v, 10)
+}
+
+// Name returns metric name.
+func (g *IntGauge) Name() string {
+	return g.name
+}
+
+// Copy returns copy of gauge. It needs for snapshots.
+func (g *IntGauge) Copy() Metric {
+	return &IntGauge{value: atomic.LoadInt64(&g.value), name: g.name, described: g.described}
+}
+
+// Reset flushes gauge value. It needs for snapshots.
+func (g *IntGauge) Reset() {
+	atomic.StoreInt64(&g.value, 0)
+}
+
+// Swap returns copy of gauge and flushes its value atomically. It needs for snapshots.
+func (g *IntGauge) Swap() Metric {
+	return &IntGauge{value: atomic.SwapInt64(&g.value, 0), name: g.name, described: g.described}
+}
+
+// ResetPolicy returns KeepValue, gauge holds the current value and isn't flushed by snapshots.
+func (g *IntGauge) ResetPolicy() ResetPolicy {
+	return KeepValue
+}
@@ -0,0 +1,242 @@
+package metrics
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Interval of exponentially weighted moving averages update.
+const meterTickInterval = 5 * time.Second
+
+// ewma is an exponentially weighted moving average of rate per second.
+type ewma struct {
+	alpha float64
+	rate  float64
+	init  bool
+}
+
+// newEWMA returns moving average for given period in minutes.
+func newEWMA(minutes float64) ewma {
+	return ewma{alpha: 1 - math.Exp(-meterTickInterval.Seconds()/60/minutes)}
+}
+
+// tick updates moving average with count of events happened during the tick interval.
+func (e *ewma) tick(count uint64) {
+	instant := float64(count) / meterTickInterval.Seconds()
+	if e.init {
+		e.rate += e.alpha * (instant - e.rate)
+	} else {
+		e.rate = instant
+		e.init = true
+	}
+}
+
+// idle updates moving average with n ticks without events.
+func (e *ewma) idle(n float64) {
+	e.rate *= math.Pow(1-e.alpha, n)
+}
+
+// Meter is a metric that measures the rate of events.
+// It keeps the total count, the mean rate and 1, 5 and 15 minutes exponentially weighted moving average rates.
+// Rates are updated each 5 seconds on metric access, so meter doesn't need any background goroutine.
+// Its value is the one minute rate, so it's of gauge kind.
+// Satsfies Metric interface.
+type Meter struct {
+	name string
+	described
+
+	mu        sync.Mutex
+	count     uint64
+	uncounted uint64
+	start     time.Time
+	lastTick  time.Time
+	rates     [3]ewma
+	// Frozen meters are snapshot copies which values don't change over time.
+	frozen   bool
+	frozenAt time.Time
+}
+
+// NewMeter returns new meter that satsfies Metric interface.
+func NewMeter(name string, opts ...Option) *Meter {
+	now := time.Now()
+	return &Meter{
+		name:      name,
+		described: newDescribed(KindGauge, opts),
+		start:     now,
+		lastTick:  now,
+		rates:     [3]ewma{newEWMA(1), newEWMA(5), newEWMA(15)},
+	}
+}
+
+// Mark records n events.
+func (m *Meter) Mark(n uint64) {
+	m.mu.Lock()
+	m.tick()
+	m.count += n
+	m.uncounted += n
+	m.mu.Unlock()
+}
+
+// Get returns one minute rate of events per second.
+func (m *Meter) Get() interface{} {
+	return m.Rate1()
+}
+
+// Value returns one minute rate of events per second.
+func (m *Meter) Value() float64 {
+	return m.Rate1()
+}
+
+// Sample returns count, mean rate and moving average rates of meter.
+func (m *Meter) Sample() Sample {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tick()
+	return Sample{
+		Name:  m.name,
+		Kind:  m.meta.Kind,
+		Value: m.rates[0].rate,
+		Fields: map[string]float64{
+			"count":     float64(m.count),
+			"mean_rate": m.rateMean(),
+			"rate1":     m.rates[0].rate,
+			"rate5":     m.rates[1].rate,
+			"rate15":    m.rates[2].rate,
+		},
+	}
+}
+
+// Count returns count of events.
+func (m *Meter) Count() uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.count
+}
+
+// RateMean returns mean rate of events per second since meter creation or the last snapshot.
+func (m *Meter) RateMean() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.rateMean()
+}
+
+// Rate1 returns one minute moving average rate of events per second.
+func (m *Meter) Rate1() float64 {
+	return m.rate(0)
+}
+
+// Rate5 returns five minutes moving average rate of events per second.
+func (m *Meter) Rate5() float64 {
+	return m.rate(1)
+}
+
+// Rate15 returns fifteen minutes moving average rate of events per second.
+func (m *Meter) Rate15() float64 {
+	return m.rate(2)
+}
+
+// String returns formated representation of meter, e.g. "count=100 mean=2.50/s 1m=3.10/s 5m=1.20/s 15m=0.40/s".
+func (m *Meter) String() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tick()
+	return "count=" + strconv.FormatUint(m.count, 10) +
+		" mean=" + formatRate(m.rateMean()) +
+		" 1m=" + formatRate(m.rates[0].rate) +
+		" 5m=" + formatRate(m.rates[1].rate) +
+		" 15m=" + formatRate(m.rates[2].rate)
+}
+
+// Name returns metric name.
+func (m *Meter) Name() string {
+	return m.name
+}
+
+// Copy returns copy of meter. It needs for snapshots.
+func (m *Meter) Copy() Metric {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tick()
+	return &Meter{
+		name:      m.name,
+		described: m.described,
+		count:     m.count,
+		start:     m.start,
+		lastTick:  m.lastTick,
+		rates:     m.rates,
+		frozen:    true,
+		frozenAt:  time.Now(),
+	}
+}
+
+// Reset flushes meter count and mean rate. Moving averages are kept. It needs for snapshots.
+func (m *Meter) Reset() {
+	m.mu.Lock()
+	m.tick()
+	m.count = 0
+	m.start = time.Now()
+	m.mu.Unlock()
+}
+
+// Swap returns copy of meter and flushes its count and mean rate atomically. It needs for snapshots.
+func (m *Meter) Swap() Metric {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tick()
+	now := time.Now()
+	cp := &Meter{
+		name:      m.name,
+		described: m.described,
+		count:     m.count,
+		start:     m.start,
+		lastTick:  m.lastTick,
+		rates:     m.rates,
+		frozen:    true,
+		frozenAt:  now,
+	}
+	m.count = 0
+	m.start = now
+	return cp
+}
+
+func (m *Meter) rate(idx int) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tick()
+	return m.rates[idx].rate
+}
+
+func (m *Meter) rateMean() float64 {
+	end := time.Now()
+	if m.frozen {
+		end = m.frozenAt
+	}
+	elapsed := end.Sub(m.start).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(m.count) / elapsed
+}
+
+// tick updates moving averages for all tick intervals elapsed since the last update.
+func (m *Meter) tick() {
+	if m.frozen {
+		return
+	}
+	n := time.Since(m.lastTick) / meterTickInterval
+	if n <= 0 {
+		return
+	}
+	m.lastTick = m.lastTick.Add(n * meterTickInterval)
+	for i := range m.rates {
+		m.rates[i].tick(m.uncounted)
+		m.rates[i].idle(float64(n - 1))
+	}
+	m.uncounted = 0
+}
+
+func formatRate(r float64) string {
+	return strconv.FormatFloat(r, 'f', 2, 64) + "/s"
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMeter(t *testing.T) {
+	m := NewMeter("tmeter")
+	m.Mark(10)
+	m.Mark(5)
+
+	if m.Count() != 15 {
+		t.Errorf("count mismatch, expected 15, but got %d", m.Count())
+	}
+	// meter value is a rate, so it's a gauge
+	if k := m.Metadata().Kind; k != KindGauge {
+		t.Errorf("kind mismatch, expected %s, but got %s", KindGauge, k)
+	}
+	if m.RateMean() <= 0 {
+		t.Errorf("mean rate should be positive, got %f", m.RateMean())
+	}
+	if m.Rate1() != 0 {
+		t.Errorf("one minute rate should be 0 before the first tick, got %f", m.Rate1())
+	}
+
+	// pretend that a tick interval passed
+	m.lastTick = m.lastTick.Add(-meterTickInterval)
+	if m.Rate1() != 3 || m.Rate5() != 3 || m.Rate15() != 3 {
+		t.Errorf("rates mismatch after the first tick, got %f, %f, %f", m.Rate1(), m.Rate5(), m.Rate15())
+	}
+
+	// and then a minute without events
+	m.lastTick = m.lastTick.Add(-time.Minute)
+	if r := m.Rate1(); math.Abs(r-3/math.E) > 1e-9 {
+		t.Errorf("one minute rate should decay to %f, got %f", 3/math.E, r)
+	}
+	if m.Rate15() <= m.Rate1() {
+		t.Errorf("fifteen minutes rate should decay slower than one minute rate")
+	}
+}
+
+func TestMeterSnapshot(t *testing.T) {
+	m := NewMeter("tmeter")
+	m.Mark(10)
+	m.lastTick = m.lastTick.Add(-meterTickInterval)
+
+	cp := m.Copy().(*Meter)
+	m.Reset()
+
+	if m.Count() != 0 {
+		t.Errorf("count should be flushed, got %d", m.Count())
+	}
+	if m.Rate1() != 2 {
+		t.Errorf("moving average should not be flushed, got %f", m.Rate1())
+	}
+	if cp.Count() != 10 || cp.Rate1() != 2 {
+		t.Errorf("snapshot mismatch, got count %d and rate %f", cp.Count(), cp.Rate1())
+	}
+
+	// snapshot is frozen and doesn't decay
+	cp.lastTick = cp.lastTick.Add(-time.Minute)
+	if cp.Rate1() != 2 {
+		t.Errorf("snapshot rate should not change, got %f", cp.Rate1())
+	}
+}
@@ -4,6 +4,7 @@
 package metrics
 
 // Metric is an abstract type of metric.
+// Metrics may implement Snapshotter and Resetter interfaces to take part in TrackRegistry snapshots.
 type Metric interface {
 	// Get returns a metric value.
 	Get() interface{}
@@ -11,8 +12,25 @@ type Metric interface {
 	String() string
 	// Name returns metric name.
 	Name() string
-	// fluhes the metric values.
-	flush()
-	// copy returns a new metric object with current values.
-	copy() Metric
+}
+
+// Snapshotter is implemented by metrics that can copy themselves for snapshots.
+// Metrics that don't implement it are stored in snapshots as a frozen value of Get and String.
+type Snapshotter interface {
+	// Copy returns a new metric object with current values.
+	Copy() Metric
+}
+
+// Resetter is implemented by metrics that should start a new interval after each snapshot,
+// e.g. counters that count events per interval.
+type Resetter interface {
+	// Reset flushes the metric values.
+	Reset()
+}
+
+// Swapper is implemented by metrics that can atomically take a copy of values and flush them.
+// TrackRegistry prefers it over Copy and Reset, so updates between copy and reset are never lost.
+type Swapper interface {
+	// Swap returns a new metric object with current values and flushes the metric.
+	Swap() Metric
 }
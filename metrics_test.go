@@ -1,6 +1,9 @@
 package metrics_test
 
 import (
+	"reflect"
+	"strconv"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -16,6 +19,28 @@ func BenchmarkCounter(b *testing.B) {
 	}
 }
 
+func BenchmarkCounterParallel(b *testing.B) {
+	m1 := metrics.NewCounter("Total_connections")
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			m1.Inc()
+		}
+	})
+}
+
+func BenchmarkCounterShardedParallel(b *testing.B) {
+	m1 := metrics.NewCounter("Total_connections", metrics.WithShards(0))
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			m1.Inc()
+		}
+	})
+}
+
 func BenchmarkGauge(b *testing.B) {
 	m1 := metrics.NewGauge("Total connections")
 
@@ -25,6 +50,28 @@ func BenchmarkGauge(b *testing.B) {
 	}
 }
 
+func BenchmarkGaugeParallel(b *testing.B) {
+	m1 := metrics.NewGauge("Total connections")
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			m1.Add(1e-13)
+		}
+	})
+}
+
+func BenchmarkGaugeShardedParallel(b *testing.B) {
+	m1 := metrics.NewGauge("Total connections", metrics.WithShards(0))
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			m1.Add(1e-13)
+		}
+	})
+}
+
 func TestNewTrackRegistry(t *testing.T) {
 	_, err := metrics.NewTrackRegistry("newswap", 10, time.Second*100, true)
 	if err != nil {
@@ -157,20 +204,36 @@ func TestSnapshots(t *testing.T) {
 		t.Errorf("error on registry creation: %s", err)
 	}
 	g := metrics.NewGauge("testggauge")
+	dg := metrics.NewGauge("testdeltagauge")
 
-	r.AddMetrics(g)
+	r.AddMetrics(g, dg)
+	if err := r.SetMetricResetPolicy("testdeltagauge", metrics.ResetOnSnapshot); err != nil {
+		t.Errorf("unable to set reset policy: %s", err)
+	}
 
 	for i := 0; i < 6; i++ {
 		g.Add(10)
+		dg.Add(10)
 		time.Sleep(time.Second)
 	}
 
+	// gauges keep their values by default
+	assertGauge(t, 60, g.Get())
+
 	for _, s := range r.GetSnapshots() {
 		m, err := s.GetMetricByName("testggauge")
 		if err != nil {
 			t.Errorf("unable to get metric from snapshot: %s", err)
 		}
-		assertGauge(t, 60, m.Get().(float64)+g.Get().(float64))
+		if v := m.Get().(float64); v != 50 && v != 60 {
+			t.Errorf("gauge snapshot should hold the value at snapshot time, got %f", v)
+		}
+
+		m, err = s.GetMetricByName("testdeltagauge")
+		if err != nil {
+			t.Errorf("unable to get metric from snapshot: %s", err)
+		}
+		assertGauge(t, 60, m.Get().(float64)+dg.Get().(float64))
 
 		if tshould.Round(time.Second) != s.GetTimestamp().Round(time.Second) {
 			t.Errorf("snapshot time mismatch, expected %s, but got %s", tshould.Format("15:04:05"), s.GetTimestamp().Format("15:04:05"))
@@ -178,6 +241,166 @@ func TestSnapshots(t *testing.T) {
 	}
 }
 
+func TestResetPolicy(t *testing.T) {
+	r, _ := metrics.NewTrackRegistry("testResetPolicy", 10, time.Second, false)
+	r.SetResetPolicy(metrics.Cumulative)
+
+	c := metrics.NewCounter("cumulative counter")
+	kc := metrics.NewCounter("kept counter")
+	r.AddMetrics(c, kc)
+	r.SetMetricResetPolicy("kept counter", metrics.KeepValue)
+
+	err := r.SetMetricResetPolicy("unknown", metrics.KeepValue)
+	switch err.(type) {
+	case metrics.ErrMetricUnknown:
+	default:
+		t.Errorf("should be unknown metric error, but got %v", err)
+	}
+
+	c.Add(10)
+	kc.Add(10)
+	time.Sleep(time.Second + time.Millisecond*50)
+	c.Add(5)
+	kc.Add(5)
+	time.Sleep(time.Second)
+
+	assertCounter(t, 15, c.Get())
+	assertCounter(t, 15, kc.Get())
+
+	sn := r.GetSnapshots()
+	for i, expected := range []uint64{5, 10} {
+		m, _ := sn[i].GetMetricByName("cumulative counter")
+		assertCounter(t, expected, m.Get())
+	}
+	for i, expected := range []uint64{15, 10} {
+		m, _ := sn[i].GetMetricByName("kept counter")
+		assertCounter(t, expected, m.Get())
+	}
+}
+
+func TestFuncMetrics(t *testing.T) {
+	var queue, total int64 = 3, 42
+	g := metrics.NewGaugeFunc("queue length", func() float64 { return float64(atomic.LoadInt64(&queue)) })
+	c := metrics.NewCounterFunc("pool total", func() uint64 { return uint64(atomic.LoadInt64(&total)) })
+
+	assertGauge(t, 3, g.Get())
+	assertCounter(t, 42, c.Get())
+
+	atomic.AddInt64(&queue, 1)
+	atomic.AddInt64(&total, 1)
+	assertGauge(t, 4, g.Get())
+	if c.String() != "43" {
+		t.Errorf("unexpected string representation: %s", c.String())
+	}
+
+	rg, _ := metrics.NewTrackRegistry("testFuncMetrics", 10, time.Second, false)
+	rg.AddMetrics(g, c)
+
+	time.Sleep(time.Second + time.Millisecond*50)
+	atomic.StoreInt64(&queue, 1)
+	assertGauge(t, 1, g.Get())
+
+	m, err := rg.GetSnapshots()[0].GetMetricByName("queue length")
+	if err != nil {
+		t.Errorf("error on getting metric: %v", err)
+	}
+	assertGauge(t, 4, m.Get())
+}
+
+// userMetric is a metric implemented outside of the package.
+type userMetric struct {
+	value int64
+}
+
+func (m *userMetric) Get() interface{} { return atomic.LoadInt64(&m.value) }
+func (m *userMetric) String() string   { return strconv.FormatInt(atomic.LoadInt64(&m.value), 10) }
+func (m *userMetric) Name() string     { return "user metric" }
+
+// resettableMetric is a metric implemented outside of the package that takes part in snapshots.
+type resettableMetric struct {
+	userMetric
+}
+
+func (m *resettableMetric) Name() string { return "resettable metric" }
+func (m *resettableMetric) Copy() metrics.Metric {
+	return &resettableMetric{userMetric{atomic.LoadInt64(&m.value)}}
+}
+func (m *resettableMetric) Reset() { atomic.StoreInt64(&m.value, 0) }
+
+func TestUserMetrics(t *testing.T) {
+	rg, _ := metrics.NewTrackRegistry("testUserMetrics", 10, time.Second, false)
+	um := &userMetric{value: 5}
+	rm := &resettableMetric{userMetric{value: 7}}
+	if err := rg.AddMetrics(um, rm); err != nil {
+		t.Errorf("unable to register metrics: %v", err)
+	}
+
+	time.Sleep(time.Second + time.Millisecond*50)
+	atomic.AddInt64(&um.value, 1)
+
+	if um.Get().(int64) != 6 || rm.Get().(int64) != 0 {
+		t.Errorf("only resetters should be reset, got %v and %v", um.Get(), rm.Get())
+	}
+
+	sn := rg.GetSnapshots()[0]
+	m, _ := sn.GetMetricByName("user metric")
+	if m.Get().(int64) != 5 || m.String() != "5" {
+		t.Errorf("snapshot should keep the frozen value, got %v", m.Get())
+	}
+	m, _ = sn.GetMetricByName("resettable metric")
+	if m.Get().(int64) != 7 {
+		t.Errorf("snapshot mismatch, expected 7, but got %v", m.Get())
+	}
+}
+
+func TestValueOf(t *testing.T) {
+	c := metrics.NewCounter("vcounter")
+	c.Add(3)
+	g := metrics.NewGauge("vgauge")
+	g.Set(1.5)
+	h := metrics.NewHistogram("vhistogram", []float64{1}, metrics.WithKind(metrics.KindUnknown))
+	h.Observe(0.5)
+	h.Observe(2)
+	v := metrics.NewCounterVec("vvector", "code")
+	v.WithLabelValues("200").Add(2)
+	v.WithLabelValues("500").Add(1)
+
+	for _, tc := range []struct {
+		m        metrics.Metric
+		expected float64
+	}{
+		{c, 3},
+		{g, 1.5},
+		{h, 2},
+		{v, 3},
+		{&userMetric{value: 7}, 7},
+	} {
+		val, ok := metrics.ValueOf(tc.m)
+		if !ok || val != tc.expected {
+			t.Errorf("value of %s mismatch, expected %f, but got %f", tc.m.Name(), tc.expected, val)
+		}
+	}
+
+	if _, ok := metrics.ValueOf(metrics.NewTopK("vtopk", 3)); ok {
+		t.Error("top-k should have no numeric value")
+	}
+
+	if c.Kind() != metrics.KindCounter || g.Kind() != metrics.KindGauge || h.Kind() != metrics.KindUnknown {
+		t.Errorf("kinds mismatch, got %s, %s and %s", c.Kind(), g.Kind(), h.Kind())
+	}
+
+	s, _ := metrics.SampleOf(h)
+	expected := map[string]float64{"count": 2, "sum": 2.5, "le_1": 1, "le_+Inf": 1}
+	if !reflect.DeepEqual(expected, s.Fields) {
+		t.Errorf("histogram sample mismatch, expected %v, but got %v", expected, s.Fields)
+	}
+
+	s, _ = metrics.SampleOf(c)
+	if s.Name != "vcounter" || s.Kind != metrics.KindCounter || s.Value != 3 {
+		t.Errorf("counter sample mismatch, got %v", s)
+	}
+}
+
 func assertGauge(t *testing.T, expected float64, actual interface{}) {
 	if expected != actual.(float64) {
 		t.Errorf("gauge mismatch, expected %f, but got %f", expected, actual)
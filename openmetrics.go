@@ -0,0 +1,256 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OpenMetricsContentType is a content type of OpenMetrics text format.
+const OpenMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// WriteOpenMetrics writes current values of registry metrics in OpenMetrics text format.
+// Metric names are sanitized and suffixed with units, e.g. "request time" with unit "seconds"
+// becomes "request_time_seconds". Counters and histogram buckets carry their exemplars.
+// Samples of counters are suffixed with "_total", so it is trimmed from counter family names.
+// Metrics without numeric values (e.g. TopK) are skipped.
+//
+// Note that metrics of TrackRegistry are flushed by snapshots unless they have KeepValue or Cumulative reset policy.
+func WriteOpenMetrics(w io.Writer, r Registry) error {
+	ms := r.GetMetrics()
+	names := make([]string, 0, len(ms))
+	for name := range ms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	bw := bufio.NewWriter(w)
+	for _, name := range names {
+		writeOpenMetricsFamily(bw, ms[name])
+	}
+	bw.WriteString("# EOF\n")
+	return bw.Flush()
+}
+
+// writeOpenMetricsFamily writes metadata and samples of metric family.
+func writeOpenMetricsFamily(w *bufio.Writer, m Metric) {
+	meta := metadataOf(m)
+	name := openMetricsName(m.Name(), meta.Unit)
+	if _, ok := m.(*Meter); ok || meta.Kind == KindCounter {
+		// _total is a suffix of counter samples, it isn't a part of family name
+		name = strings.TrimSuffix(name, "_total")
+	}
+
+	var samples []openMetricsSample
+	switch v := m.(type) {
+	case *Histogram:
+		meta.Kind = KindHistogram
+		samples = histogramSamples(name, v)
+	case *Summary:
+		meta.Kind = KindSummary
+		quantiles := make(map[float64]float64)
+		for q, val := range v.Get().(map[float64]float64) {
+			quantiles[q] = val
+		}
+		samples = summarySamples(name, v.Sample(), quantiles)
+	case *Timer:
+		meta.Kind = KindSummary
+		quantiles := make(map[float64]float64)
+		for q, d := range v.Get().(map[float64]time.Duration) {
+			quantiles[q] = d.Seconds()
+		}
+		samples = summarySamples(name, v.Sample(), quantiles)
+	case *StateSet:
+		meta.Kind = KindStateSet
+		current := v.State()
+		for _, st := range v.States() {
+			s := openMetricsSample{name: name, labels: name + `="` + escapeLabelValue(st) + `"`}
+			if st == current {
+				s.value = 1
+			}
+			samples = append(samples, s)
+		}
+	case *Info:
+		meta.Kind = KindInfo
+		var ls []string
+		values := v.Values()
+		for _, k := range v.Keys() {
+			ls = append(ls, sanitizeOpenMetricsName(k)+`="`+escapeLabelValue(values[k])+`"`)
+		}
+		samples = []openMetricsSample{{name: name + "_info", labels: strings.Join(ls, ","), value: 1}}
+	case *Meter:
+		meta.Kind = KindCounter
+		samples = []openMetricsSample{{name: name + "_total", value: float64(v.Count())}}
+	case vector:
+		vec := v.vec()
+		for _, ch := range vec.list() {
+			val, ok := ValueOf(ch.metric)
+			if !ok {
+				continue
+			}
+			s := openMetricsSample{name: name, labels: vectorLabels(vec.labelNames, ch.values), value: val}
+			if meta.Kind == KindCounter {
+				s.name += "_total"
+			}
+			if ex := exemplarsOf(ch.metric); len(ex) > 0 {
+				s.exemplar = &ex[0]
+			}
+			samples = append(samples, s)
+		}
+	default:
+		val, ok := ValueOf(m)
+		if !ok {
+			return
+		}
+		s := openMetricsSample{name: name, value: val}
+		switch meta.Kind {
+		case KindCounter:
+			s.name += "_total"
+			if ex := exemplarsOf(m); len(ex) > 0 {
+				s.exemplar = &ex[0]
+			}
+		case KindHistogram, KindSummary:
+			// single value of custom metric can't be a histogram or summary
+			meta.Kind = KindUnknown
+		}
+		samples = []openMetricsSample{s}
+	}
+
+	w.WriteString("# TYPE " + name + " " + string(meta.Kind) + "\n")
+	if meta.Unit != "" {
+		w.WriteString("# UNIT " + name + " " + sanitizeOpenMetricsName(meta.Unit) + "\n")
+	}
+	if meta.Description != "" {
+		w.WriteString("# HELP " + name + " " + escapeHelp(meta.Description) + "\n")
+	}
+	for _, s := range samples {
+		s.write(w)
+	}
+}
+
+// openMetricsSample is a single line of OpenMetrics text format.
+type openMetricsSample struct {
+	name string
+	// Formatted labels, e.g. `code="200"`
+	labels   string
+	value    float64
+	exemplar *Exemplar
+}
+
+func (s openMetricsSample) write(w *bufio.Writer) {
+	w.WriteString(s.name)
+	if s.labels != "" {
+		w.WriteString("{" + s.labels + "}")
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatOpenMetricsValue(s.value))
+	if s.exemplar != nil {
+		w.WriteString(" # ")
+		w.WriteString(s.exemplar.String())
+	}
+	w.WriteByte('\n')
+}
+
+// histogramSamples returns cumulative buckets with exemplars, count and sum of histogram.
+func histogramSamples(name string, h *Histogram) []openMetricsSample {
+	cp := h.Copy().(*Histogram)
+	var (
+		ret []openMetricsSample
+		cum uint64
+	)
+	for i, b := range cp.Buckets() {
+		cum += b.Count
+		s := openMetricsSample{
+			name:     name + "_bucket",
+			labels:   `le="` + formatOpenMetricsValue(b.UpperBound) + `"`,
+			value:    float64(cum),
+			exemplar: cp.exemplars[i].e,
+		}
+		ret = append(ret, s)
+	}
+	return append(ret,
+		openMetricsSample{name: name + "_count", value: float64(cum)},
+		openMetricsSample{name: name + "_sum", value: cp.Sum()},
+	)
+}
+
+// summarySamples returns quantiles, count and sum of summary.
+func summarySamples(name string, smp Sample, quantiles map[float64]float64) []openMetricsSample {
+	qs := make([]float64, 0, len(quantiles))
+	for q := range quantiles {
+		qs = append(qs, q)
+	}
+	sort.Float64s(qs)
+
+	ret := make([]openMetricsSample, 0, len(qs)+2)
+	for _, q := range qs {
+		ret = append(ret, openMetricsSample{
+			name:   name,
+			labels: `quantile="` + formatOpenMetricsValue(q) + `"`,
+			value:  quantiles[q],
+		})
+	}
+	return append(ret,
+		openMetricsSample{name: name + "_count", value: smp.Fields["count"]},
+		openMetricsSample{name: name + "_sum", value: smp.Fields["sum"]},
+	)
+}
+
+// vectorLabels returns formatted labels of vector child, e.g. `code="200",method="GET"`.
+func vectorLabels(names, values []string) string {
+	ls := make([]string, len(names))
+	for i, n := range names {
+		ls[i] = sanitizeOpenMetricsName(n) + `="` + escapeLabelValue(values[i]) + `"`
+	}
+	return strings.Join(ls, ",")
+}
+
+// openMetricsName returns valid OpenMetrics name of metric family suffixed with unit.
+func openMetricsName(name, unit string) string {
+	name = sanitizeOpenMetricsName(name)
+	if unit == "" {
+		return name
+	}
+	unit = sanitizeOpenMetricsName(unit)
+	if !strings.HasSuffix(name, "_"+unit) {
+		name += "_" + unit
+	}
+	return name
+}
+
+// sanitizeOpenMetricsName replaces characters that aren't allowed in OpenMetrics names with underscores.
+func sanitizeOpenMetricsName(name string) string {
+	b := []byte(name)
+	for i, c := range b {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_', c == ':':
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
+
+var (
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+// escapeLabelValue escapes backslashes, double quotes and line feeds of label value.
+func escapeLabelValue(v string) string {
+	return labelValueEscaper.Replace(v)
+}
+
+// escapeHelp escapes backslashes and line feeds of help text.
+func escapeHelp(v string) string {
+	return helpEscaper.Replace(v)
+}
+
+// formatOpenMetricsValue formats value as OpenMetrics number, e.g. "0.5", "+Inf" or "NaN".
+func formatOpenMetricsValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,67 @@
+package metrics_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/admobi/easy-metrics"
+)
+
+func TestWriteOpenMetrics(t *testing.T) {
+	r, err := metrics.NewRegistry("openmetricsreg")
+	if err != nil {
+		t.Fatalf("unable to create registry: %s", err)
+	}
+
+	c := metrics.NewCounter("http requests", metrics.WithDescription("Number of requests"))
+	c.AddWithExemplar(3, map[string]string{"trace_id": "abc"})
+	g := metrics.NewGauge("heap", metrics.WithUnit("bytes"))
+	g.Set(1024)
+	h := metrics.NewHistogram("latency", []float64{0.1, 1}, metrics.WithUnit("seconds"))
+	h.Observe(0.05)
+	h.ObserveWithExemplar(0.5, map[string]string{"trace_id": "def"})
+	v := metrics.NewCounterVec("responses", "code")
+	v.WithLabelValues("200").Add(2)
+	st := metrics.NewStateSet("breaker", []string{"closed", "open"})
+	st.Set("open")
+	info := metrics.NewInfo("build", map[string]string{"version": "1.2.0", "commit": "1a2b3c"})
+	total := metrics.NewCounter("jobs_total")
+	total.Inc()
+	r.AddMetrics(c, g, h, v, st, info, total, metrics.NewTopK("pages", 3))
+
+	var buf bytes.Buffer
+	if err := metrics.WriteOpenMetrics(&buf, r); err != nil {
+		t.Fatalf("unable to write metrics: %s", err)
+	}
+	out := buf.String()
+
+	for _, s := range []string{
+		"# TYPE heap_bytes gauge\n# UNIT heap_bytes bytes\nheap_bytes 1024\n",
+		"# TYPE http_requests counter\n# HELP http_requests Number of requests\n",
+		`http_requests_total 3 # {trace_id="abc"} 3 `,
+		"# TYPE latency_seconds histogram\n",
+		"latency_seconds_bucket{le=\"0.1\"} 1\n",
+		`latency_seconds_bucket{le="1"} 2 # {trace_id="def"} 0.5 `,
+		"latency_seconds_bucket{le=\"+Inf\"} 2\n",
+		"latency_seconds_count 2\n",
+		"latency_seconds_sum 0.55\n",
+		"responses_total{code=\"200\"} 2\n",
+		"# TYPE jobs counter\njobs_total 1\n",
+		"# TYPE build info\nbuild_info{commit=\"1a2b3c\",version=\"1.2.0\"} 1\n",
+		"# TYPE breaker stateset\nbreaker{breaker=\"closed\"} 0\nbreaker{breaker=\"open\"} 1\n",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("OpenMetrics output should contain %q, got %s", s, out)
+		}
+	}
+	if strings.Contains(out, "_total_total") {
+		t.Errorf("counter family name should not end with _total, got %s", out)
+	}
+	if strings.Contains(out, "pages") {
+		t.Errorf("metrics without numeric value should be skipped, got %s", out)
+	}
+	if !strings.HasSuffix(out, "# EOF\n") {
+		t.Errorf("OpenMetrics output should end with EOF, got %s", out)
+	}
+}
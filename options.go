@@ -0,0 +1,151 @@
+package metrics
+
+import (
+	"runtime"
+	"strconv"
+)
+
+// Kind is a kind of metric. Kinds are named after OpenMetrics metric types.
+type Kind string
+
+// Kinds of metrics
+const (
+	KindUnknown   Kind = "unknown"
+	KindCounter   Kind = "counter"
+	KindGauge     Kind = "gauge"
+	KindHistogram Kind = "histogram"
+	KindSummary   Kind = "summary"
+	KindStateSet  Kind = "stateset"
+	KindInfo      Kind = "info"
+)
+
+// Metadata describes a metric.
+type Metadata struct {
+	// Description is a human readable help text.
+	Description string
+	// Unit of metric values, e.g. "seconds" or "bytes".
+	Unit string
+	// Kind of metric.
+	Kind Kind
+}
+
+// Describer is implemented by metrics with metadata.
+type Describer interface {
+	// Metadata returns metric metadata.
+	Metadata() Metadata
+}
+
+// Option configures a metric on creation.
+type Option func(*options)
+
+// options is a set of optional metric parameters.
+type options struct {
+	meta   Metadata
+	format Formatter
+	// Number of shards, zero if metric isn't sharded
+	shards int
+}
+
+// newOptions returns options of metric with given default kind.
+func newOptions(kind Kind, opts []Option) options {
+	o := options{meta: Metadata{Kind: kind}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithDescription sets human readable description of metric.
+func WithDescription(description string) Option {
+	return func(o *options) {
+		o.meta.Description = description
+	}
+}
+
+// WithUnit sets unit of metric values, e.g. "seconds" or "bytes".
+func WithUnit(unit string) Option {
+	return func(o *options) {
+		o.meta.Unit = unit
+	}
+}
+
+// WithKind overrides default kind of metric.
+func WithKind(kind Kind) Option {
+	return func(o *options) {
+		o.meta.Kind = kind
+	}
+}
+
+// WithFormatter sets formatter of metric values. It's used by String method and on charts.
+func WithFormatter(f Formatter) Option {
+	return func(o *options) {
+		o.format = f
+	}
+}
+
+// WithShards makes counter or gauge sharded for heavily contended hot paths.
+// Updates are spread over n cells padded to cache line, so concurrent goroutines don't contend
+// on a single value. Reading sums all cells, and uncontended updates are slower than updates
+// of not sharded metric, so it pays off only when many goroutines update the metric on multiple CPUs.
+// If n is not positive GOMAXPROCS shards are used. Other metrics ignore this option.
+//
+//	c := NewCounter("requests", WithShards(0))
+func WithShards(n int) Option {
+	return func(o *options) {
+		if n <= 0 {
+			n = runtime.GOMAXPROCS(0)
+		}
+		o.shards = n
+	}
+}
+
+// described is embedded into metrics with metadata and formatter.
+type described struct {
+	meta   Metadata
+	format Formatter
+}
+
+// newDescribed returns metadata and formatter of metric with given default kind.
+func newDescribed(kind Kind, opts []Option) described {
+	o := newOptions(kind, opts)
+	return described{meta: o.meta, format: o.format}
+}
+
+// Metadata returns metric metadata.
+func (d described) Metadata() Metadata {
+	return d.meta
+}
+
+// Kind returns kind of metric.
+func (d described) Kind() Kind {
+	return d.meta.Kind
+}
+
+// formatter returns formatter of metric values.
+func (d described) formatter() Formatter {
+	return d.format
+}
+
+// formatFloat returns value formatted by metric formatter.
+func (d described) formatFloat(v float64) string {
+	if d.format != nil {
+		return d.format(v)
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// formatUint returns value formatted by metric formatter.
+func (d described) formatUint(v uint64) string {
+	if d.format != nil {
+		return d.format(float64(v))
+	}
+	return strconv.FormatUint(v, 10)
+}
+
+// metadataOf returns metadata of metric, metrics without metadata are of unknown kind.
+func metadataOf(m Metric) Metadata {
+	if d, ok := m.(Describer); ok {
+		return d.Metadata()
+	}
+	return Metadata{Kind: KindUnknown}
+}
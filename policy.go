@@ -0,0 +1,42 @@
+package metrics
+
+// ResetPolicy defines how TrackRegistry handles metric values on snapshots.
+type ResetPolicy int
+
+const (
+	// ResetOnSnapshot flushes metric after each snapshot, so snapshots hold values per interval (delta).
+	// It's the default policy.
+	ResetOnSnapshot ResetPolicy = iota
+	// KeepValue never flushes metric, so snapshots hold the value at snapshot time (gauge semantics).
+	KeepValue
+	// Cumulative never flushes metric, but snapshots hold the difference with the previous snapshot.
+	// Metrics that don't implement Differ are stored with the value at snapshot time.
+	Cumulative
+)
+
+// String returns name of reset policy.
+func (p ResetPolicy) String() string {
+	switch p {
+	case ResetOnSnapshot:
+		return "reset on snapshot"
+	case KeepValue:
+		return "keep value"
+	case Cumulative:
+		return "cumulative"
+	}
+	return "unknown"
+}
+
+// ResetPolicer is implemented by metrics with their own default reset policy, e.g. gauges keep their values.
+type ResetPolicer interface {
+	// ResetPolicy returns default reset policy of metric.
+	ResetPolicy() ResetPolicy
+}
+
+// Differ is implemented by metrics that can be stored in snapshots as a difference
+// between the current and the previous values. It's used by Cumulative policy.
+type Differ interface {
+	// Diff returns a new metric object with difference between metric and prev.
+	// Prev is a snapshot copy of the same metric.
+	Diff(prev Metric) Metric
+}
@@ -0,0 +1,166 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+)
+
+// Size of insert buffer of quantile stream.
+const quantileBufSize = 500
+
+// quantileSample is a sample of quantile stream.
+type quantileSample struct {
+	value float64
+	// Difference between the lowest rank of this sample and the previous one.
+	width float64
+	// Difference between the highest and the lowest rank of this sample.
+	delta float64
+}
+
+// quantileStream is a not thread safe implementation of the CKMS algorithm for targeted quantiles
+// over data streams ("Effective Computation of Biased Quantiles over Data Streams",
+// Cormode, Korn, Muthukrishnan, Srivastava).
+// It keeps only the samples needed to answer the targeted quantiles within their errors,
+// so memory usage is bounded by the targets instead of the number of observations.
+type quantileStream struct {
+	// Targeted quantiles with allowed errors.
+	targets map[float64]float64
+	samples []quantileSample
+	buf     []float64
+	n       float64
+}
+
+func newQuantileStream(targets map[float64]float64) *quantileStream {
+	return &quantileStream{
+		targets: targets,
+		buf:     make([]float64, 0, quantileBufSize),
+	}
+}
+
+// insert adds value into stream.
+func (s *quantileStream) insert(v float64) {
+	s.buf = append(s.buf, v)
+	if len(s.buf) == cap(s.buf) {
+		s.flush()
+	}
+}
+
+// query returns approximated value of quantile q. It returns NaN on empty stream.
+func (s *quantileStream) query(q float64) float64 {
+	s.flush()
+	if len(s.samples) == 0 {
+		return math.NaN()
+	}
+
+	t := math.Ceil(q * s.n)
+	t += math.Ceil(s.invariant(t) / 2)
+	p := s.samples[0]
+	var r float64
+	for _, c := range s.samples[1:] {
+		r += p.width
+		if r+c.width+c.delta > t {
+			return p.value
+		}
+		p = c
+	}
+	return p.value
+}
+
+// count returns number of values in stream.
+func (s *quantileStream) count() uint64 {
+	return uint64(s.n) + uint64(len(s.buf))
+}
+
+// reset removes all values from stream.
+func (s *quantileStream) reset() {
+	s.samples = s.samples[:0]
+	s.buf = s.buf[:0]
+	s.n = 0
+}
+
+// copy returns a deep copy of stream.
+func (s *quantileStream) copy() *quantileStream {
+	s.flush()
+	return &quantileStream{
+		targets: s.targets,
+		samples: append([]quantileSample(nil), s.samples...),
+		buf:     make([]float64, 0, quantileBufSize),
+		n:       s.n,
+	}
+}
+
+// invariant returns the maximum allowed rank error for rank r.
+func (s *quantileStream) invariant(r float64) float64 {
+	m := math.MaxFloat64
+	for q, eps := range s.targets {
+		var f float64
+		if q*s.n <= r {
+			f = 2 * eps * r / q
+		} else {
+			f = 2 * eps * (s.n - r) / (1 - q)
+		}
+		if f < m {
+			m = f
+		}
+	}
+	return m
+}
+
+// flush merges buffered values into samples and compresses them.
+func (s *quantileStream) flush() {
+	if len(s.buf) == 0 {
+		return
+	}
+	sort.Float64s(s.buf)
+
+	var r float64
+	i := 0
+	for _, v := range s.buf {
+		inserted := false
+		for ; i < len(s.samples); i++ {
+			c := s.samples[i]
+			if c.value > v {
+				smp := quantileSample{value: v, width: 1, delta: math.Max(0, math.Floor(s.invariant(r))-1)}
+				s.samples = append(s.samples, quantileSample{})
+				copy(s.samples[i+1:], s.samples[i:])
+				s.samples[i] = smp
+				i++
+				inserted = true
+				break
+			}
+			r += c.width
+		}
+		if !inserted {
+			s.samples = append(s.samples, quantileSample{value: v, width: 1})
+			i++
+		}
+		s.n++
+		r++
+	}
+	s.buf = s.buf[:0]
+	s.compress()
+}
+
+// compress merges adjacent samples while the invariant allows it.
+func (s *quantileStream) compress() {
+	if len(s.samples) < 2 {
+		return
+	}
+	xi := len(s.samples) - 1
+	x := s.samples[xi]
+	r := s.n - 1 - x.width
+
+	for i := len(s.samples) - 2; i >= 0; i-- {
+		c := s.samples[i]
+		if c.width+x.width+x.delta <= s.invariant(r) {
+			x.width += c.width
+			s.samples[xi] = x
+			s.samples = append(s.samples[:i], s.samples[i+1:]...)
+			xi--
+		} else {
+			x = c
+			xi = i
+		}
+		r -= c.width
+	}
+}
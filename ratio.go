@@ -0,0 +1,140 @@
+package metrics
+
+import (
+	"math"
+	"sync"
+)
+
+// Ratio is a metric that represents a fraction of events, e.g. cache hit rate or share of successful requests.
+// It counts numerator and denominator, so snapshots hold the ratio per interval rather than a lifetime average.
+// Satsfies Metric interface.
+type Ratio struct {
+	name string
+	described
+
+	mu          sync.Mutex
+	numerator   uint64
+	denominator uint64
+}
+
+// NewRatio returns new ratio that satsfies Metric interface.
+// Its values are formatted as percentage unless other formatter is set by options.
+func NewRatio(name string, opts ...Option) *Ratio {
+	opts = append([]Option{WithFormatter(FormatPercent)}, opts...)
+	return &Ratio{name: name, described: newDescribed(KindGauge, opts)}
+}
+
+// Hit counts a successful event, e.g. cache hit.
+func (r *Ratio) Hit() {
+	r.Add(1, 1)
+}
+
+// Miss counts an unsuccessful event, e.g. cache miss.
+func (r *Ratio) Miss() {
+	r.Add(0, 1)
+}
+
+// Add adds deltas to numerator and denominator of ratio.
+func (r *Ratio) Add(numerator, denominator uint64) {
+	r.mu.Lock()
+	r.numerator += numerator
+	r.denominator += denominator
+	r.mu.Unlock()
+}
+
+// Get returns ratio as a fraction, e.g. 0.75. It returns NaN if there were no events.
+func (r *Ratio) Get() interface{} {
+	return r.Value()
+}
+
+// Value returns ratio as a fraction. It returns NaN if there were no events.
+func (r *Ratio) Value() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.ratio()
+}
+
+// Sample returns ratio with its numerator and denominator.
+func (r *Ratio) Sample() Sample {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return Sample{
+		Name:  r.name,
+		Kind:  r.meta.Kind,
+		Value: r.ratio(),
+		Fields: map[string]float64{
+			"numerator":   float64(r.numerator),
+			"denominator": float64(r.denominator),
+		},
+	}
+}
+
+// Numerator returns count of successful events.
+func (r *Ratio) Numerator() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.numerator
+}
+
+// Denominator returns count of all events.
+func (r *Ratio) Denominator() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.denominator
+}
+
+// String returns formated representation of ratio, e.g. "75%". It returns "NaN" if there were no events.
+func (r *Ratio) String() string {
+	v := r.Value()
+	if math.IsNaN(v) {
+		return "NaN"
+	}
+	return r.formatFloat(v)
+}
+
+// Name returns metric name.
+func (r *Ratio) Name() string {
+	return r.name
+}
+
+// Copy returns copy of ratio. It needs for snapshots.
+func (r *Ratio) Copy() Metric {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &Ratio{name: r.name, described: r.described, numerator: r.numerator, denominator: r.denominator}
+}
+
+// Reset flushes ratio values. It needs for snapshots.
+func (r *Ratio) Reset() {
+	r.mu.Lock()
+	r.numerator, r.denominator = 0, 0
+	r.mu.Unlock()
+}
+
+// Swap returns copy of ratio and flushes its values atomically. It needs for snapshots.
+func (r *Ratio) Swap() Metric {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cp := &Ratio{name: r.name, described: r.described, numerator: r.numerator, denominator: r.denominator}
+	r.numerator, r.denominator = 0, 0
+	return cp
+}
+
+// Diff returns ratio of events happened since prev. It needs for cumulative snapshots.
+// If ratio values are less than prev (e.g. it was reset) the current values are returned.
+func (r *Ratio) Diff(prev Metric) Metric {
+	cp := r.Copy().(*Ratio)
+	if p, ok := prev.(*Ratio); ok && p.numerator <= cp.numerator && p.denominator <= cp.denominator {
+		cp.numerator -= p.numerator
+		cp.denominator -= p.denominator
+	}
+	return cp
+}
+
+// ratio returns fraction of numerator and denominator.
+func (r *Ratio) ratio() float64 {
+	if r.denominator == 0 {
+		return math.NaN()
+	}
+	return float64(r.numerator) / float64(r.denominator)
+}
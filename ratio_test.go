@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRatio(t *testing.T) {
+	r := NewRatio("tratio")
+	if v := r.Get().(float64); !math.IsNaN(v) {
+		t.Errorf("ratio without events is expected to be NaN, but got %v", v)
+	}
+	if s := r.String(); s != "NaN" {
+		t.Errorf("ratio without events is expected to be shown as NaN, but got %s", s)
+	}
+
+	for i := 0; i < 3; i++ {
+		r.Hit()
+	}
+	r.Miss()
+	if v := r.Get().(float64); v != 0.75 {
+		t.Errorf("ratio is expected to be 0.75, but got %v", v)
+	}
+	if s := r.String(); s != "75%" {
+		t.Errorf("ratio is expected to be shown as 75%%, but got %s", s)
+	}
+
+	r.Add(1, 4)
+	if n, d := r.Numerator(), r.Denominator(); n != 4 || d != 8 {
+		t.Errorf("ratio is expected to be 4/8, but got %d/%d", n, d)
+	}
+
+	cp := r.Swap().(*Ratio)
+	if v := cp.Value(); v != 0.5 {
+		t.Errorf("swapped ratio is expected to be 0.5, but got %v", v)
+	}
+	if d := r.Denominator(); d != 0 {
+		t.Errorf("ratio should be flushed by swap, but got denominator %d", d)
+	}
+}
+
+func TestRatioSnapshots(t *testing.T) {
+	r, err := NewTrackRegistry("testRatioSnapshots", 10, time.Hour, false)
+	if err != nil {
+		t.Fatalf("unable to create registry: %s", err)
+	}
+	tr := r.(*TrackRegistry)
+
+	ratio := NewRatio("ratio")
+	r.AddMetrics(ratio)
+
+	ratio.Hit()
+	ratio.Hit()
+	tr.makeSnapshot()
+	ratio.Hit()
+	ratio.Miss()
+	ratio.Miss()
+	ratio.Miss()
+	tr.makeSnapshot()
+
+	// snapshots hold the ratio of each window, the newest one goes first
+	for i, expected := range []float64{0.25, 1} {
+		m, err := r.GetSnapshots()[i].GetMetricByName("ratio")
+		if err != nil {
+			t.Fatalf("unable to get metric from snapshot: %s", err)
+		}
+		if v := m.Get().(float64); v != expected {
+			t.Errorf("ratio of snapshot %d is expected to be %v, but got %v", i, expected, v)
+		}
+	}
+}
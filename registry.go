@@ -121,6 +121,8 @@ func (r *DefaultRegistry) GetMetrics() map[string]Metric {
 type Tracker interface {
 	Registry
 	GetSnapshots() []Snapshot
+	SetResetPolicy(p ResetPolicy)
+	SetMetricResetPolicy(name string, p ResetPolicy) error
 }
 
 // TrackRegistry is a registry that can stores the pool of snapshoted metrics.
@@ -129,6 +131,11 @@ type TrackRegistry struct {
 	duration time.Duration
 	// Metric snapshots container
 	buf []Snapshot
+	// Default reset policy and policies of particular metrics
+	policy   ResetPolicy
+	policies map[string]ResetPolicy
+	// Previous copies of cumulative metrics
+	prev map[string]Metric
 	DefaultRegistry
 }
 
@@ -159,6 +166,17 @@ func (am *Snapshot) GetMetrics() map[string]Metric {
 	return am.data
 }
 
+// GetExemplars returns exemplars of snapshoted metrics by metric names
+func (am *Snapshot) GetExemplars() map[string][]Exemplar {
+	ret := make(map[string][]Exemplar)
+	for name, m := range am.data {
+		if ex := exemplarsOf(m); len(ex) > 0 {
+			ret[name] = ex
+		}
+	}
+	return ret
+}
+
 // NewTrackRegistry creates a new TrackRegistry and adds it into the registry map.
 // It makes the snapshots of metric on each interval and keeps it in pool with specified capacity.
 // If align is set to true, metric's archiving will be align by interval duration.
@@ -189,6 +207,8 @@ func NewTrackRegistry(name string, capacity int, interval time.Duration, align b
 	trackReg := &TrackRegistry{
 		buf:      make([]Snapshot, 0, capacity),
 		duration: interval,
+		policies: make(map[string]ResetPolicy),
+		prev:     make(map[string]Metric),
 	}
 
 	trackReg.metrics = make(map[string]Metric)
@@ -221,6 +241,41 @@ func (r *TrackRegistry) GetSnapshots() []Snapshot {
 	return sn
 }
 
+// SetResetPolicy sets default reset policy of registry.
+// It's used for metrics that have neither own policy (see ResetPolicer) nor policy set by SetMetricResetPolicy.
+func (r *TrackRegistry) SetResetPolicy(p ResetPolicy) {
+	r.Lock()
+	r.policy = p
+	r.Unlock()
+}
+
+// SetMetricResetPolicy sets reset policy of registered metric with given name.
+// It overrides own policy of metric and default policy of registry.
+func (r *TrackRegistry) SetMetricResetPolicy(name string, p ResetPolicy) error {
+	if len(name) == 0 {
+		return ErrEmptyMetricName{}
+	}
+	r.Lock()
+	defer r.Unlock()
+
+	if _, ok := r.metrics[name]; !ok {
+		return ErrMetricUnknown(name)
+	}
+	r.policies[name] = p
+	return nil
+}
+
+// resetPolicy returns reset policy of metric
+func (r *TrackRegistry) resetPolicy(name string, m Metric) ResetPolicy {
+	if p, ok := r.policies[name]; ok {
+		return p
+	}
+	if p, ok := m.(ResetPolicer); ok {
+		return p.ResetPolicy()
+	}
+	return r.policy
+}
+
 // Timer for metrics swapping
 func (r *TrackRegistry) startTimer() {
 	for {
@@ -237,19 +292,41 @@ func (r *TrackRegistry) makeSnapshot() {
 	r.Lock()
 	defer r.Unlock()
 
-	r.shiftSlice()
-
 	if len(r.buf) == 0 || len(r.buf) < cap(r.buf) {
 		swmetric := Snapshot{}
 		swmetric.data = make(map[string]Metric)
 		r.buf = append(r.buf, swmetric)
 	}
-	r.buf[0].data = copyMetrics(r.metrics)
+
+	r.shiftSlice()
+	r.buf[0].data = r.snapshotMetrics()
 	r.buf[0].t = time.Now().UTC()
+}
+
+// Returns copies of metrics for snapshot according to their reset policies
+func (r *TrackRegistry) snapshotMetrics() map[string]Metric {
+	ret := make(map[string]Metric, len(r.metrics))
+	for name, m := range r.metrics {
+		policy := r.resetPolicy(name, m)
+		if policy == ResetOnSnapshot {
+			ret[name] = swapMetric(m)
+			continue
+		}
 
-	for n := range r.metrics {
-		r.metrics[n].flush()
+		cp := copyMetric(m)
+		ret[name] = cp
+		if policy == Cumulative {
+			d, ok := cp.(Differ)
+			if !ok {
+				continue
+			}
+			if prev, ok := r.prev[name]; ok {
+				ret[name] = d.Diff(prev)
+			}
+			r.prev[name] = cp
+		}
 	}
+	return ret
 }
 
 // Shift snapshots slice by 1 and pops oldest snapshot
@@ -260,10 +337,52 @@ func (r *TrackRegistry) shiftSlice() {
 	}
 }
 
-func copyMetrics(src map[string]Metric) map[string]Metric {
-	ret := make(map[string]Metric)
-	for name, m := range src {
-		ret[name] = m.copy()
+// copyMetric returns copy of metric for snapshot.
+// Metrics that don't implement Snapshotter are copied as a frozen value.
+func copyMetric(m Metric) Metric {
+	if s, ok := m.(Snapshotter); ok {
+		return s.Copy()
 	}
-	return ret
+	return &frozenMetric{name: m.Name(), described: described{meta: metadataOf(m)}, value: m.Get(), str: m.String()}
+}
+
+// swapMetric returns copy of metric for snapshot and flushes metric values.
+// Metrics that don't implement Swapper are copied and then reset, so concurrent updates between may be lost.
+func swapMetric(m Metric) Metric {
+	if s, ok := m.(Swapper); ok {
+		return s.Swap()
+	}
+	cp := copyMetric(m)
+	resetMetric(m)
+	return cp
+}
+
+// resetMetric flushes metric values if metric implements Resetter.
+func resetMetric(m Metric) {
+	if r, ok := m.(Resetter); ok {
+		r.Reset()
+	}
+}
+
+// frozenMetric is a snapshot copy of metric that doesn't implement Snapshotter.
+type frozenMetric struct {
+	name string
+	described
+	value interface{}
+	str   string
+}
+
+// Get returns metric value at the snapshot time.
+func (m *frozenMetric) Get() interface{} {
+	return m.value
+}
+
+// String returns formatted value of metric at the snapshot time.
+func (m *frozenMetric) String() string {
+	return m.str
+}
+
+// Name returns metric name.
+func (m *frozenMetric) Name() string {
+	return m.name
 }
@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLosslessSnapshots(t *testing.T) {
+	const workers, iterations = 8, 20000
+
+	r, err := NewTrackRegistry("testLosslessSnapshots", 1, time.Hour, false)
+	if err != nil {
+		t.Fatalf("unable to create registry: %s", err)
+	}
+	tr := r.(*TrackRegistry)
+
+	c := NewCounter("counter")
+	g := NewGauge("gauge")
+	h := NewHistogram("histogram", LinearBuckets(1, 1, 3))
+	v := NewCounterVec("vector", "worker")
+	sc := NewCounter("sharded", WithShards(4))
+	r.AddMetrics(c, g, h, v, sc)
+	r.SetMetricResetPolicy("gauge", ResetOnSnapshot)
+
+	var counter, histogram, vector, sharded uint64
+	var gauge float64
+	collect := func() {
+		tr.makeSnapshot()
+		data := tr.GetSnapshots()[0].GetMetrics()
+		counter += data["counter"].Get().(uint64)
+		sharded += data["sharded"].Get().(uint64)
+		gauge += data["gauge"].Get().(float64)
+		histogram += data["histogram"].(*Histogram).Count()
+		for _, cnt := range data["vector"].Get().(map[string]uint64) {
+			vector += cnt
+		}
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				collect()
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			child := v.WithLabelValues(string(rune('a' + w)))
+			for i := 0; i < iterations; i++ {
+				c.Inc()
+				g.Add(1)
+				h.Observe(float64(i % 4))
+				child.Inc()
+				sc.Inc()
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(done)
+	<-stopped
+	collect()
+
+	const total = workers * iterations
+	if counter != total || gauge != total || histogram != total || vector != total || sharded != total {
+		t.Errorf("lost updates between snapshots, expected %d, but got counter %d, gauge %f, histogram %d, vector %d, sharded %d",
+			total, counter, gauge, histogram, vector, sharded)
+	}
+}
@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+)
+
+// Size of CPU cache line. Cells of sharded values are padded to it to avoid false sharing.
+const cacheLineSize = 64
+
+// paddedCell is a value that occupies the whole cache line.
+type paddedCell struct {
+	value uint64
+	_     [cacheLineSize - 8]byte
+}
+
+// shards is a striped value for heavily contended metrics.
+// Each goroutine updates one of cells, the value is the sum of all cells.
+// Cells are chosen by hints kept in sync.Pool, which holds objects per P,
+// so goroutines running on the same P update the same cell most of the time.
+type shards struct {
+	cells []paddedCell
+	hints sync.Pool
+	next  uint32
+}
+
+// newShards returns n shards, n must be positive.
+func newShards(n int) *shards {
+	s := &shards{cells: make([]paddedCell, n)}
+	s.hints.New = func() interface{} {
+		idx := int(atomic.AddUint32(&s.next, 1)-1) % len(s.cells)
+		return &idx
+	}
+	return s
+}
+
+// cell returns cell of the current goroutine.
+func (s *shards) cell() *uint64 {
+	hint := s.hints.Get().(*int)
+	c := &s.cells[*hint].value
+	s.hints.Put(hint)
+	return c
+}
+
+// addUint64 adds delta to integer value.
+func (s *shards) addUint64(delta uint64) {
+	atomic.AddUint64(s.cell(), delta)
+}
+
+// loadUint64 returns sum of integer cells.
+func (s *shards) loadUint64() uint64 {
+	var sum uint64
+	for i := range s.cells {
+		sum += atomic.LoadUint64(&s.cells[i].value)
+	}
+	return sum
+}
+
+// swapUint64 returns sum of integer cells and flushes them.
+// Each cell is swapped atomically, so every update gets into exactly one sum.
+func (s *shards) swapUint64() uint64 {
+	var sum uint64
+	for i := range s.cells {
+		sum += atomic.SwapUint64(&s.cells[i].value, 0)
+	}
+	return sum
+}
+
+// addFloat64 adds delta to float value.
+func (s *shards) addFloat64(delta float64) {
+	c := s.cell()
+	for {
+		cur := atomic.LoadUint64(c)
+		nxt := math.Float64bits(math.Float64frombits(cur) + delta)
+		if atomic.CompareAndSwapUint64(c, cur, nxt) {
+			return
+		}
+	}
+}
+
+// loadFloat64 returns sum of float cells.
+func (s *shards) loadFloat64() float64 {
+	var sum float64
+	for i := range s.cells {
+		sum += math.Float64frombits(atomic.LoadUint64(&s.cells[i].value))
+	}
+	return sum
+}
+
+// swapFloat64 returns sum of float cells and flushes them.
+func (s *shards) swapFloat64() float64 {
+	var sum float64
+	for i := range s.cells {
+		sum += math.Float64frombits(atomic.SwapUint64(&s.cells[i].value, 0))
+	}
+	return sum
+}
+
+// store sets value of the first cell to v and flushes others.
+// It's not atomic with concurrent updates of other cells.
+func (s *shards) store(v uint64) {
+	atomic.StoreUint64(&s.cells[0].value, v)
+	for i := 1; i < len(s.cells); i++ {
+		atomic.StoreUint64(&s.cells[i].value, 0)
+	}
+}
@@ -0,0 +1,61 @@
+package metrics_test
+
+import (
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/admobi/easy-metrics"
+)
+
+func TestShardedCounter(t *testing.T) {
+	c := metrics.NewCounter("tshardedcounter", metrics.WithShards(4))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				c.Inc()
+			}
+		}()
+	}
+	wg.Wait()
+	assertCounter(t, 8000, c.Get())
+
+	cp := c.Swap()
+	assertCounter(t, 8000, cp.Get())
+	assertCounter(t, 0, c.Get())
+
+	c.Add(5)
+	c.Reset()
+	assertCounter(t, 0, c.Get())
+}
+
+func TestShardedGauge(t *testing.T) {
+	g := metrics.NewGauge("tshardedgauge", metrics.WithShards(0))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				g.Add(0.5)
+			}
+		}()
+	}
+	wg.Wait()
+	assertGauge(t, 4000, g.Get())
+
+	g.Set(42)
+	assertGauge(t, 42, g.Get())
+	g.Sub(2)
+	assertGauge(t, 40, g.Copy().Get())
+
+	if v := g.Swap().Get().(float64); math.Abs(v-40) > 1e-9 {
+		t.Errorf("swapped gauge value is expected to be 40, but got %v", v)
+	}
+	assertGauge(t, 0, g.Get())
+}
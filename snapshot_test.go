@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnapshotsOrder(t *testing.T) {
+	r, err := NewTrackRegistry("testSnapshotsOrder", 3, time.Hour, false)
+	if err != nil {
+		t.Fatalf("unable to create registry: %s", err)
+	}
+	tr := r.(*TrackRegistry)
+
+	c := NewCounter("counter")
+	r.AddMetrics(c)
+
+	// the newest snapshot goes first, the oldest one is dropped when buffer is full
+	for i, expected := range [][]uint64{{1}, {2, 1}, {3, 2, 1}, {4, 3, 2}} {
+		c.Add(uint64(i + 1))
+		tr.makeSnapshot()
+
+		sn := r.GetSnapshots()
+		if len(sn) != len(expected) {
+			t.Fatalf("number of snapshots is expected to be %d, but got %d", len(expected), len(sn))
+		}
+		for j, v := range expected {
+			m, err := sn[j].GetMetricByName("counter")
+			if err != nil {
+				t.Fatalf("unable to get metric from snapshot %d: %s", j, err)
+			}
+			if m.Get().(uint64) != v {
+				t.Errorf("snapshot %d is expected to hold %d, but got %d", j, v, m.Get())
+			}
+		}
+	}
+}
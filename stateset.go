@@ -0,0 +1,223 @@
+package metrics
+
+import (
+	"bytes"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// StateSet is a metric that represents one of discrete states, e.g. circuit breaker state or node role.
+// It records time spent in each state and number of transitions since the last snapshot.
+// Snapshots restart the statistics, the current state is kept.
+// Satsfies Metric interface.
+type StateSet struct {
+	name string
+	described
+	states []string
+
+	mu          sync.Mutex
+	current     int
+	durations   []time.Duration
+	transitions uint64
+	// Time of the last update of durations
+	since time.Time
+	// Frozen state sets are snapshot copies which durations don't change over time.
+	frozen bool
+}
+
+// NewStateSet returns new state set with allowed states that satsfies Metric interface.
+// The first state is the initial one. On charts states are drawn by their indexes labeled with names.
+//
+//	s := NewStateSet("breaker", []string{"closed", "half-open", "open"})
+func NewStateSet(name string, states []string, opts ...Option) *StateSet {
+	states = append([]string(nil), states...)
+	opts = append([]Option{WithFormatter(stateFormatter(states))}, opts...)
+	return &StateSet{
+		name:      name,
+		described: newDescribed(KindStateSet, opts),
+		states:    states,
+		durations: make([]time.Duration, len(states)),
+		since:     time.Now(),
+	}
+}
+
+// Set switches state set into state. It returns ErrStateUnknown if state isn't declared.
+// Setting the current state isn't counted as a transition.
+func (s *StateSet) Set(state string) error {
+	idx := s.index(state)
+	if idx < 0 {
+		return ErrStateUnknown(state)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if idx == s.current {
+		return nil
+	}
+	s.advance(time.Now())
+	s.current = idx
+	s.transitions++
+	return nil
+}
+
+// State returns the current state.
+func (s *StateSet) State() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stateName()
+}
+
+// States returns allowed states.
+func (s *StateSet) States() []string {
+	return s.states
+}
+
+// Durations returns time spent in each state since the last snapshot by state names.
+func (s *StateSet) Durations() map[string]time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.advance(time.Now())
+	ret := make(map[string]time.Duration, len(s.states))
+	for i, st := range s.states {
+		ret[st] = s.durations[i]
+	}
+	return ret
+}
+
+// Transitions returns number of transitions since the last snapshot.
+func (s *StateSet) Transitions() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.transitions
+}
+
+// Get returns the current state.
+func (s *StateSet) Get() interface{} {
+	return s.State()
+}
+
+// Sample returns index of the current state as value with number of transitions
+// and time spent in each state in seconds, e.g. "open_seconds".
+func (s *StateSet) Sample() Sample {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.advance(time.Now())
+	smp := Sample{
+		Name:   s.name,
+		Kind:   s.meta.Kind,
+		Value:  float64(s.current),
+		Fields: map[string]float64{"transitions": float64(s.transitions)},
+	}
+	for i, st := range s.states {
+		smp.Fields[st+"_seconds"] = s.durations[i].Seconds()
+	}
+	return smp
+}
+
+// String returns the current state with time spent in each state and number of transitions,
+// e.g. "open closed=50s open=10s transitions=1".
+func (s *StateSet) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.advance(time.Now())
+
+	var buf bytes.Buffer
+	buf.WriteString(s.stateName())
+	for i, st := range s.states {
+		buf.WriteByte(' ')
+		buf.WriteString(st)
+		buf.WriteByte('=')
+		buf.WriteString(roundDuration(s.durations[i], time.Millisecond).String())
+	}
+	buf.WriteString(" transitions=")
+	buf.WriteString(strconv.FormatUint(s.transitions, 10))
+	return buf.String()
+}
+
+// Name returns metric name.
+func (s *StateSet) Name() string {
+	return s.name
+}
+
+// Copy returns copy of state set. It needs for snapshots.
+func (s *StateSet) Copy() Metric {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.freeze(time.Now())
+}
+
+// Reset restarts statistics of state set, the current state is kept. It needs for snapshots.
+func (s *StateSet) Reset() {
+	s.mu.Lock()
+	s.restart(time.Now())
+	s.mu.Unlock()
+}
+
+// Swap returns copy of state set and restarts its statistics atomically. It needs for snapshots.
+func (s *StateSet) Swap() Metric {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	cp := s.freeze(now)
+	s.restart(now)
+	return cp
+}
+
+func (s *StateSet) index(state string) int {
+	for i, st := range s.states {
+		if st == state {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *StateSet) stateName() string {
+	if len(s.states) == 0 {
+		return ""
+	}
+	return s.states[s.current]
+}
+
+// advance adds time spent in the current state up to now.
+func (s *StateSet) advance(now time.Time) {
+	if s.frozen || len(s.states) == 0 {
+		return
+	}
+	s.durations[s.current] += now.Sub(s.since)
+	s.since = now
+}
+
+func (s *StateSet) restart(now time.Time) {
+	for i := range s.durations {
+		s.durations[i] = 0
+	}
+	s.transitions = 0
+	s.since = now
+}
+
+// freeze returns frozen copy of state set at now.
+func (s *StateSet) freeze(now time.Time) *StateSet {
+	s.advance(now)
+	return &StateSet{
+		name:        s.name,
+		described:   s.described,
+		states:      s.states,
+		current:     s.current,
+		durations:   append([]time.Duration(nil), s.durations...),
+		transitions: s.transitions,
+		since:       now,
+		frozen:      true,
+	}
+}
+
+// stateFormatter returns formatter of state indexes, non-integer values are formatted as empty strings.
+func stateFormatter(states []string) Formatter {
+	return func(v float64) string {
+		i := int(v)
+		if float64(i) != v || i < 0 || i >= len(states) {
+			return ""
+		}
+		return states[i]
+	}
+}
@@ -0,0 +1,55 @@
+package metrics_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/admobi/easy-metrics"
+)
+
+func TestStateSet(t *testing.T) {
+	s := metrics.NewStateSet("tstateset", []string{"closed", "half-open", "open"})
+	if st := s.Get().(string); st != "closed" {
+		t.Errorf("initial state is expected to be closed, but got %s", st)
+	}
+
+	if _, ok := s.Set("broken").(metrics.ErrStateUnknown); !ok {
+		t.Errorf("unknown state should be rejected with ErrStateUnknown")
+	}
+	s.Set("open")
+	time.Sleep(20 * time.Millisecond)
+	s.Set("open")
+	s.Set("half-open")
+
+	if st := s.State(); st != "half-open" {
+		t.Errorf("current state is expected to be half-open, but got %s", st)
+	}
+	if n := s.Transitions(); n != 2 {
+		t.Errorf("number of transitions is expected to be 2, but got %d", n)
+	}
+	if d := s.Durations()["open"]; d < 20*time.Millisecond {
+		t.Errorf("time in open state is expected to be at least 20ms, but got %s", d)
+	}
+	if str := s.String(); !strings.HasPrefix(str, "half-open closed=") || !strings.HasSuffix(str, " transitions=2") {
+		t.Errorf("unexpected state set string %q", str)
+	}
+
+	cp := s.Swap().(*metrics.StateSet)
+	open := cp.Durations()["open"]
+	time.Sleep(10 * time.Millisecond)
+	if d := cp.Durations()["open"]; d != open {
+		t.Errorf("durations of swapped state set must not change, but got %s and %s", open, d)
+	}
+
+	// statistics are restarted, the current state is kept
+	if st, n := s.State(), s.Transitions(); st != "half-open" || n != 0 {
+		t.Errorf("state set should keep state and flush transitions, but got %s and %d", st, n)
+	}
+	if d := s.Durations()["open"]; d != 0 {
+		t.Errorf("time in open state should be flushed, but got %s", d)
+	}
+	if smp, _ := metrics.SampleOf(s); smp.Value != 1 || smp.Fields["half-open_seconds"] <= 0 {
+		t.Errorf("unexpected state set sample %v", smp)
+	}
+}
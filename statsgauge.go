@@ -0,0 +1,206 @@
+package metrics
+
+import (
+	"bytes"
+	"strconv"
+	"sync"
+)
+
+// StatsGauge is a gauge that keeps statistics of all values set within snapshot interval:
+// minimum, maximum, last value, count of updates and mean value.
+// Snapshots restart the statistics, the last value is kept.
+// If there were no updates within interval, minimum, maximum and mean are the last value.
+// On charts it's drawn as the mean line with a band of minimum and maximum values.
+// Satsfies Metric interface.
+type StatsGauge struct {
+	name string
+	described
+
+	mu    sync.Mutex
+	last  float64
+	min   float64
+	max   float64
+	sum   float64
+	count uint64
+}
+
+// NewStatsGauge returns new statistics gauge that satsfies Metric interface.
+func NewStatsGauge(name string, opts ...Option) *StatsGauge {
+	return &StatsGauge{name: name, described: newDescribed(KindGauge, opts)}
+}
+
+// Set sets gauge value to value.
+func (g *StatsGauge) Set(value float64) {
+	g.mu.Lock()
+	g.set(value)
+	g.mu.Unlock()
+}
+
+// Add adds delta to gauge value.
+func (g *StatsGauge) Add(delta float64) {
+	g.mu.Lock()
+	g.set(g.last + delta)
+	g.mu.Unlock()
+}
+
+// Sub substarcts delta from gauge value.
+func (g *StatsGauge) Sub(delta float64) {
+	g.Add(-delta)
+}
+
+// Get returns the last gauge value.
+func (g *StatsGauge) Get() interface{} {
+	return g.Last()
+}
+
+// Value returns the last gauge value.
+func (g *StatsGauge) Value() float64 {
+	return g.Last()
+}
+
+// Sample returns statistics of gauge values.
+func (g *StatsGauge) Sample() Sample {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return Sample{
+		Name:  g.name,
+		Kind:  g.meta.Kind,
+		Value: g.last,
+		Fields: map[string]float64{
+			"last":  g.last,
+			"min":   g.min,
+			"max":   g.max,
+			"mean":  g.mean(),
+			"count": float64(g.count),
+		},
+	}
+}
+
+// Last returns the last gauge value.
+func (g *StatsGauge) Last() float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.last
+}
+
+// Min returns the minimum value set since the last snapshot.
+// If there were no updates it returns the last value.
+func (g *StatsGauge) Min() float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.min
+}
+
+// Max returns the maximum value set since the last snapshot.
+// If there were no updates it returns the last value.
+func (g *StatsGauge) Max() float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.max
+}
+
+// Mean returns the mean of values set since the last snapshot.
+// If there were no updates it returns the last value.
+func (g *StatsGauge) Mean() float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.mean()
+}
+
+// Count returns number of updates since the last snapshot.
+func (g *StatsGauge) Count() uint64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.count
+}
+
+// String returns formated representation of gauge statistics, e.g. "last=5 min=1 max=9 mean=4.5 count=10".
+func (g *StatsGauge) String() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var buf bytes.Buffer
+	buf.WriteString("last=")
+	buf.WriteString(g.formatFloat(g.last))
+	buf.WriteString(" min=")
+	buf.WriteString(g.formatFloat(g.min))
+	buf.WriteString(" max=")
+	buf.WriteString(g.formatFloat(g.max))
+	buf.WriteString(" mean=")
+	buf.WriteString(g.formatFloat(g.mean()))
+	buf.WriteString(" count=")
+	buf.WriteString(strconv.FormatUint(g.count, 10))
+	return buf.String()
+}
+
+// Name returns metric name.
+func (g *StatsGauge) Name() string {
+	return g.name
+}
+
+// Copy returns copy of gauge. It needs for snapshots.
+func (g *StatsGauge) Copy() Metric {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.copy()
+}
+
+// Reset restarts statistics of gauge, the last value is kept. It needs for snapshots.
+func (g *StatsGauge) Reset() {
+	g.mu.Lock()
+	g.restart()
+	g.mu.Unlock()
+}
+
+// Swap returns copy of gauge and restarts its statistics atomically. It needs for snapshots.
+func (g *StatsGauge) Swap() Metric {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	cp := g.copy()
+	g.restart()
+	return cp
+}
+
+// chartRange returns minimum, mean and maximum values to draw the mean line with a band.
+func (g *StatsGauge) chartRange() (low, mid, high float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.min, g.mean(), g.max
+}
+
+func (g *StatsGauge) set(value float64) {
+	if g.count == 0 || value < g.min {
+		g.min = value
+	}
+	if g.count == 0 || value > g.max {
+		g.max = value
+	}
+	g.last = value
+	g.sum += value
+	g.count++
+}
+
+// restart restarts statistics, minimum and maximum fall back to the last value until the next update.
+func (g *StatsGauge) restart() {
+	g.min, g.max = g.last, g.last
+	g.sum, g.count = 0, 0
+}
+
+func (g *StatsGauge) copy() *StatsGauge {
+	return &StatsGauge{
+		name:      g.name,
+		described: g.described,
+		last:      g.last,
+		min:       g.min,
+		max:       g.max,
+		sum:       g.sum,
+		count:     g.count,
+	}
+}
+
+func (g *StatsGauge) mean() float64 {
+	if g.count == 0 {
+		return g.last
+	}
+	return g.sum / float64(g.count)
+}
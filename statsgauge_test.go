@@ -0,0 +1,45 @@
+package metrics_test
+
+import (
+	"testing"
+
+	"github.com/admobi/easy-metrics"
+)
+
+func TestStatsGauge(t *testing.T) {
+	g := metrics.NewStatsGauge("tstatsgauge")
+	for _, v := range []float64{5, 9, 1} {
+		g.Set(v)
+	}
+	g.Add(2)
+
+	if v := g.Get().(float64); v != 3 {
+		t.Errorf("last value is expected to be 3, but got %v", v)
+	}
+	if s := g.String(); s != "last=3 min=1 max=9 mean=4.5 count=4" {
+		t.Errorf("unexpected gauge string %q", s)
+	}
+
+	cp := g.Swap().(*metrics.StatsGauge)
+	if smp, _ := metrics.SampleOf(cp); smp.Fields["min"] != 1 || smp.Fields["max"] != 9 || smp.Fields["mean"] != 4.5 ||
+		smp.Fields["last"] != 3 || smp.Fields["count"] != 4 {
+		t.Errorf("swapped gauge should keep statistics, but got %v", smp.Fields)
+	}
+
+	// statistics are restarted with the last value
+	if s := g.String(); s != "last=3 min=3 max=3 mean=3 count=0" {
+		t.Errorf("unexpected gauge string after swap %q", s)
+	}
+	g.Set(7)
+	if min, max, mean := g.Min(), g.Max(), g.Mean(); min != 7 || max != 7 || mean != 7 {
+		t.Errorf("unexpected statistics min=%v max=%v mean=%v", min, max, mean)
+	}
+
+	// values held before the first update aren't counted
+	fresh := metrics.NewStatsGauge("tstatsgauge_fresh")
+	fresh.Set(2)
+	fresh.Set(4)
+	if min, max := fresh.Min(), fresh.Max(); min != 2 || max != 4 {
+		t.Errorf("unexpected statistics min=%v max=%v", min, max)
+	}
+}
@@ -0,0 +1,185 @@
+package metrics
+
+import (
+	"bytes"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// DefObjectives are default targeted quantiles with their allowed errors.
+var DefObjectives = map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001}
+
+// Summary is a metric that calculates quantiles of observations over a streaming sketch.
+// It uses bounded memory regardless of observations count.
+// Satsfies Metric interface.
+type Summary struct {
+	name string
+	described
+	objectives map[float64]float64
+	// Sorted targeted quantiles
+	quantiles []float64
+
+	mu     sync.Mutex
+	stream *quantileStream
+	sum    float64
+}
+
+// NewSummary returns new summary that satsfies Metric interface.
+// Objectives defines targeted quantiles with their allowed absolute errors, e.g. {0.99: 0.001}.
+// Quantiles must be in range (0, 1), invalid ones are ignored.
+// If objectives is empty DefObjectives will be used.
+func NewSummary(name string, objectives map[float64]float64, opts ...Option) *Summary {
+	if len(objectives) == 0 {
+		objectives = DefObjectives
+	}
+
+	s := &Summary{
+		name:       name,
+		described:  newDescribed(KindSummary, opts),
+		objectives: make(map[float64]float64, len(objectives)),
+	}
+	for q, eps := range objectives {
+		if q <= 0 || q >= 1 || eps < 0 {
+			continue
+		}
+		s.objectives[q] = eps
+		s.quantiles = append(s.quantiles, q)
+	}
+	sort.Float64s(s.quantiles)
+	s.stream = newQuantileStream(s.objectives)
+
+	return s
+}
+
+// Observe adds a single observation into summary.
+func (s *Summary) Observe(value float64) {
+	s.mu.Lock()
+	s.stream.insert(value)
+	s.sum += value
+	s.mu.Unlock()
+}
+
+// Get returns map of targeted quantiles and their values.
+func (s *Summary) Get() interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ret := make(map[float64]float64, len(s.quantiles))
+	for _, q := range s.quantiles {
+		ret[q] = s.stream.query(q)
+	}
+	return ret
+}
+
+// Quantile returns value of quantile q. It returns NaN if there are no observations.
+// Only targeted quantiles are guaranteed to be in the error range.
+func (s *Summary) Quantile(q float64) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stream.query(q)
+}
+
+// Value returns total count of observations as float64.
+func (s *Summary) Value() float64 {
+	return float64(s.Count())
+}
+
+// Sample returns count, sum and targeted quantiles of summary, e.g. "p99".
+func (s *Summary) Sample() Sample {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	smp := Sample{
+		Name:   s.name,
+		Kind:   s.meta.Kind,
+		Value:  float64(s.stream.count()),
+		Fields: map[string]float64{"sum": s.sum},
+	}
+	smp.Fields["count"] = smp.Value
+	for _, q := range s.quantiles {
+		smp.Fields[quantileName(q)] = s.stream.query(q)
+	}
+	return smp
+}
+
+// Count returns total count of observations.
+func (s *Summary) Count() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stream.count()
+}
+
+// Sum returns sum of observed values.
+func (s *Summary) Sum() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sum
+}
+
+// String returns formated representation of summary, e.g. "count=10 sum=42 p50=3 p90=8 p99=9".
+func (s *Summary) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var buf bytes.Buffer
+	buf.WriteString("count=")
+	buf.WriteString(strconv.FormatUint(s.stream.count(), 10))
+	buf.WriteString(" sum=")
+	buf.WriteString(s.formatFloat(s.sum))
+	for _, q := range s.quantiles {
+		buf.WriteByte(' ')
+		buf.WriteString(quantileName(q))
+		buf.WriteByte('=')
+		buf.WriteString(s.formatFloat(s.stream.query(q)))
+	}
+	return buf.String()
+}
+
+// Name returns metric name.
+func (s *Summary) Name() string {
+	return s.name
+}
+
+// Copy returns copy of summary. It needs for snapshots.
+func (s *Summary) Copy() Metric {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &Summary{
+		name:       s.name,
+		described:  s.described,
+		objectives: s.objectives,
+		quantiles:  s.quantiles,
+		stream:     s.stream.copy(),
+		sum:        s.sum,
+	}
+}
+
+// Reset flushes summary values. It needs for snapshots.
+func (s *Summary) Reset() {
+	s.mu.Lock()
+	s.stream.reset()
+	s.sum = 0
+	s.mu.Unlock()
+}
+
+// Swap returns copy of summary and flushes its values atomically. It needs for snapshots.
+func (s *Summary) Swap() Metric {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cp := &Summary{
+		name:       s.name,
+		described:  s.described,
+		objectives: s.objectives,
+		quantiles:  s.quantiles,
+		stream:     s.stream,
+		sum:        s.sum,
+	}
+	s.stream = newQuantileStream(s.objectives)
+	s.sum = 0
+	return cp
+}
+
+// quantileName returns percentile name of quantile, e.g. p99 for 0.99.
+func quantileName(q float64) string {
+	return "p" + strconv.FormatFloat(math.Floor(q*1e6+0.5)/1e4, 'g', -1, 64)
+}
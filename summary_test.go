@@ -0,0 +1,65 @@
+package metrics_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/admobi/easy-metrics"
+)
+
+func BenchmarkSummary(b *testing.B) {
+	s := metrics.NewSummary("latency", nil)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Observe(float64(i % 1024))
+	}
+}
+
+func TestSummary(t *testing.T) {
+	const n = 100000
+	s := metrics.NewSummary("tsummary", nil)
+
+	if !math.IsNaN(s.Quantile(0.5)) {
+		t.Error("quantile of empty summary should be NaN")
+	}
+
+	for _, v := range rand.New(rand.NewSource(42)).Perm(n) {
+		s.Observe(float64(v + 1))
+	}
+
+	assertCounter(t, n, s.Count())
+	assertGauge(t, n*(n+1)/2, s.Sum())
+
+	values := s.Get().(map[float64]float64)
+	for q, eps := range metrics.DefObjectives {
+		if math.Abs(values[q]-q*n) > eps*n {
+			t.Errorf("quantile %v is out of error range, got %v", q, values[q])
+		}
+	}
+}
+
+func TestSummarySnapshot(t *testing.T) {
+	rg, _ := metrics.NewTrackRegistry("testSummarySnapshot", 10, time.Second, false)
+	s := metrics.NewSummary("summary", map[float64]float64{0.5: 0.01})
+	rg.AddMetrics(s)
+
+	for i := 1; i <= 99; i++ {
+		s.Observe(float64(i))
+	}
+
+	time.Sleep(time.Second + time.Millisecond*50)
+	assertCounter(t, 0, s.Count())
+
+	m, err := rg.GetSnapshots()[0].GetMetricByName("summary")
+	if err != nil {
+		t.Errorf("error on getting metric: %v", err)
+	}
+	sm := m.(*metrics.Summary)
+	assertCounter(t, 99, sm.Count())
+	if p50 := sm.Quantile(0.5); math.Abs(p50-50) > 1 {
+		t.Errorf("quantile 0.5 is out of error range, got %v", p50)
+	}
+}
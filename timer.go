@@ -0,0 +1,240 @@
+package metrics
+
+import (
+	"bytes"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Timer is a metric that records durations into a distribution.
+// It keeps count, min, max, mean and percentiles of durations.
+// Satsfies Metric interface.
+type Timer struct {
+	name string
+	described
+
+	mu     sync.Mutex
+	stream *quantileStream
+	sum    time.Duration
+	min    time.Duration
+	max    time.Duration
+}
+
+// Stopwatch measures a single duration for timer. It's created by Timer.Start.
+type Stopwatch struct {
+	timer *Timer
+	start time.Time
+}
+
+// Stop records the duration elapsed since stopwatch was started and returns it.
+func (s *Stopwatch) Stop() time.Duration {
+	d := time.Since(s.start)
+	s.timer.Update(d)
+	return d
+}
+
+// NewTimer returns new timer that satsfies Metric interface.
+// It tracks percentiles defined by DefObjectives at the creation time.
+func NewTimer(name string, opts ...Option) *Timer {
+	objectives := make(map[float64]float64, len(DefObjectives))
+	for q, eps := range DefObjectives {
+		objectives[q] = eps
+	}
+	return &Timer{
+		name:      name,
+		described: newDescribed(KindSummary, opts),
+		stream:    newQuantileStream(objectives),
+	}
+}
+
+// Update records duration.
+func (t *Timer) Update(d time.Duration) {
+	t.mu.Lock()
+	if t.stream.count() == 0 || d < t.min {
+		t.min = d
+	}
+	if t.stream.count() == 0 || d > t.max {
+		t.max = d
+	}
+	t.stream.insert(float64(d))
+	t.sum += d
+	t.mu.Unlock()
+}
+
+// UpdateSince records duration elapsed since ts.
+func (t *Timer) UpdateSince(ts time.Time) {
+	t.Update(time.Since(ts))
+}
+
+// Time calls function f and records its execution duration.
+func (t *Timer) Time(f func()) {
+	ts := time.Now()
+	f()
+	t.UpdateSince(ts)
+}
+
+// Start returns a started stopwatch. Duration will be recorded on stopwatch Stop call.
+//
+//	sw := timer.Start()
+//	defer sw.Stop()
+func (t *Timer) Start() *Stopwatch {
+	return &Stopwatch{timer: t, start: time.Now()}
+}
+
+// Get returns map of DefObjectives percentiles and their durations.
+func (t *Timer) Get() interface{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ret := make(map[float64]time.Duration, len(t.stream.targets))
+	for q := range t.stream.targets {
+		ret[q] = t.percentile(q)
+	}
+	return ret
+}
+
+// Value returns number of recorded durations as float64.
+func (t *Timer) Value() float64 {
+	return float64(t.Count())
+}
+
+// Sample returns count, min, max, mean and percentiles of timer. Durations are in seconds.
+func (t *Timer) Sample() Sample {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := Sample{
+		Name:  t.name,
+		Kind:  t.meta.Kind,
+		Value: float64(t.stream.count()),
+		Fields: map[string]float64{
+			"min":  t.min.Seconds(),
+			"max":  t.max.Seconds(),
+			"mean": t.mean().Seconds(),
+			"sum":  t.sum.Seconds(),
+		},
+	}
+	s.Fields["count"] = s.Value
+	for q := range t.stream.targets {
+		s.Fields[quantileName(q)] = t.percentile(q).Seconds()
+	}
+	return s
+}
+
+// Count returns number of recorded durations.
+func (t *Timer) Count() uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.stream.count()
+}
+
+// Min returns minimal recorded duration.
+func (t *Timer) Min() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.min
+}
+
+// Max returns maximal recorded duration.
+func (t *Timer) Max() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.max
+}
+
+// Mean returns mean of recorded durations.
+func (t *Timer) Mean() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.mean()
+}
+
+// Percentile returns duration of quantile q, e.g. 0.99.
+// Only DefObjectives quantiles are guaranteed to be in the error range.
+func (t *Timer) Percentile(q float64) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.percentile(q)
+}
+
+// String returns formated representation of timer with durations in human units,
+// e.g. "count=3 min=1ms mean=2ms max=3ms p50=2ms p90=3ms p99=3ms".
+func (t *Timer) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var buf bytes.Buffer
+	buf.WriteString("count=")
+	buf.WriteString(strconv.FormatUint(t.stream.count(), 10))
+	buf.WriteString(" min=")
+	buf.WriteString(t.min.String())
+	buf.WriteString(" mean=")
+	buf.WriteString(t.mean().String())
+	buf.WriteString(" max=")
+	buf.WriteString(t.max.String())
+	for _, q := range []float64{0.5, 0.9, 0.99} {
+		buf.WriteByte(' ')
+		buf.WriteString(quantileName(q))
+		buf.WriteByte('=')
+		buf.WriteString(t.percentile(q).String())
+	}
+	return buf.String()
+}
+
+// Name returns metric name.
+func (t *Timer) Name() string {
+	return t.name
+}
+
+// Copy returns copy of timer. It needs for snapshots.
+func (t *Timer) Copy() Metric {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return &Timer{
+		name:      t.name,
+		described: t.described,
+		stream:    t.stream.copy(),
+		sum:       t.sum,
+		min:       t.min,
+		max:       t.max,
+	}
+}
+
+// Reset flushes timer values. It needs for snapshots.
+func (t *Timer) Reset() {
+	t.mu.Lock()
+	t.stream.reset()
+	t.sum, t.min, t.max = 0, 0, 0
+	t.mu.Unlock()
+}
+
+// Swap returns copy of timer and flushes its values atomically. It needs for snapshots.
+func (t *Timer) Swap() Metric {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	cp := &Timer{
+		name:      t.name,
+		described: t.described,
+		stream:    t.stream,
+		sum:       t.sum,
+		min:       t.min,
+		max:       t.max,
+	}
+	t.stream = newQuantileStream(t.stream.targets)
+	t.sum, t.min, t.max = 0, 0, 0
+	return cp
+}
+
+func (t *Timer) mean() time.Duration {
+	cnt := t.stream.count()
+	if cnt == 0 {
+		return 0
+	}
+	return t.sum / time.Duration(cnt)
+}
+
+func (t *Timer) percentile(q float64) time.Duration {
+	if t.stream.count() == 0 {
+		return 0
+	}
+	return time.Duration(t.stream.query(q))
+}
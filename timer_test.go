@@ -0,0 +1,71 @@
+package metrics_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/admobi/easy-metrics"
+)
+
+func TestTimer(t *testing.T) {
+	tm := metrics.NewTimer("ttimer")
+
+	for i := 1; i <= 100; i++ {
+		tm.Update(time.Duration(i) * time.Millisecond)
+	}
+
+	assertCounter(t, 100, tm.Count())
+	if tm.Min() != time.Millisecond || tm.Max() != 100*time.Millisecond {
+		t.Errorf("min/max mismatch, got %s/%s", tm.Min(), tm.Max())
+	}
+	if tm.Mean() != 50500*time.Microsecond {
+		t.Errorf("mean mismatch, got %s", tm.Mean())
+	}
+	if p := tm.Percentile(0.99); p < 98*time.Millisecond || p > 100*time.Millisecond {
+		t.Errorf("percentile 0.99 is out of error range, got %s", p)
+	}
+
+	tm.Time(func() { time.Sleep(time.Millisecond) })
+	sw := tm.Start()
+	if d := sw.Stop(); d <= 0 {
+		t.Errorf("stopwatch duration should be positive, got %s", d)
+	}
+	tm.UpdateSince(time.Now().Add(-time.Second))
+	assertCounter(t, 103, tm.Count())
+	if tm.Max() < time.Second {
+		t.Errorf("max should be at least 1s, got %s", tm.Max())
+	}
+}
+
+func TestTimerSnapshot(t *testing.T) {
+	rg, _ := metrics.NewTrackRegistry("testTimerSnapshot", 10, time.Second, false)
+	tm := metrics.NewTimer("timer")
+	rg.AddMetrics(tm)
+
+	tm.Update(time.Millisecond)
+	tm.Update(3 * time.Millisecond)
+
+	time.Sleep(time.Second + time.Millisecond*50)
+	assertCounter(t, 0, tm.Count())
+
+	m, err := rg.GetSnapshots()[0].GetMetricByName("timer")
+	if err != nil {
+		t.Errorf("error on getting metric: %v", err)
+	}
+	if s := m.String(); !strings.HasPrefix(s, "count=2 min=1ms mean=2ms max=3ms p50=") {
+		t.Errorf("unexpected string representation: %s", s)
+	}
+}
+
+func TestTimerObjectives(t *testing.T) {
+	tm := metrics.NewTimer("ttimer_objectives")
+	tm.Update(time.Millisecond)
+
+	metrics.DefObjectives[0.75] = 0.01
+	defer delete(metrics.DefObjectives, 0.75)
+
+	if n := len(tm.Get().(map[float64]time.Duration)); n != 3 {
+		t.Errorf("timer must not be affected by changes of DefObjectives, got %d percentiles", n)
+	}
+}
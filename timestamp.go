@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// Timestamp is a metric that holds time of the last event, e.g. the last successful job run.
+// Its value is the age of the event in seconds. It holds the time until the next event,
+// so it's never reset by snapshots.
+// Satsfies Metric interface.
+type Timestamp struct {
+	// Unix time in nanoseconds, zero if there were no events.
+	// It's accessed atomically, so it goes first to be 64-bit aligned on 32-bit platforms.
+	value int64
+	name  string
+	described
+	// Age after which timestamp is stale, zero means never
+	staleAfter time.Duration
+	// Frozen timestamps are snapshot copies which age doesn't change over time.
+	frozen   bool
+	frozenAt time.Time
+}
+
+// NewTimestamp returns new timestamp that satsfies Metric interface.
+// Timestamp is stale if there were no events during staleAfter, stale values are highlighted on the dashboard.
+// Zero staleAfter means timestamp is never stale.
+func NewTimestamp(name string, staleAfter time.Duration, opts ...Option) *Timestamp {
+	return &Timestamp{name: name, described: newDescribed(KindGauge, opts), staleAfter: staleAfter}
+}
+
+// Mark sets timestamp to the current time.
+func (t *Timestamp) Mark() {
+	t.Set(time.Now())
+}
+
+// Set sets timestamp to time ts.
+func (t *Timestamp) Set(ts time.Time) {
+	atomic.StoreInt64(&t.value, ts.UnixNano())
+}
+
+// Time returns time of the last event. It returns zero time if there were no events.
+func (t *Timestamp) Time() time.Time {
+	v := atomic.LoadInt64(&t.value)
+	if v == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, v)
+}
+
+// Age returns time elapsed since the last event. It returns false if there were no events.
+func (t *Timestamp) Age() (time.Duration, bool) {
+	ts := t.Time()
+	if ts.IsZero() {
+		return 0, false
+	}
+	return t.now().Sub(ts), true
+}
+
+// Stale returns true if there were no events during stale period.
+func (t *Timestamp) Stale() bool {
+	if t.staleAfter <= 0 {
+		return false
+	}
+	age, ok := t.Age()
+	return !ok || age > t.staleAfter
+}
+
+// Get returns age of the last event in seconds. It returns NaN if there were no events.
+func (t *Timestamp) Get() interface{} {
+	return t.Value()
+}
+
+// Value returns age of the last event in seconds. It returns NaN if there were no events.
+func (t *Timestamp) Value() float64 {
+	age, ok := t.Age()
+	if !ok {
+		return math.NaN()
+	}
+	return age.Seconds()
+}
+
+// String returns formated time of the last event with its age, e.g. "2017-03-01 12:10:13 (5m3s ago)".
+// It returns "never" if there were no events.
+func (t *Timestamp) String() string {
+	age, ok := t.Age()
+	if !ok {
+		return "never"
+	}
+	return t.Time().Format("2006-01-02 15:04:05") + " (" + roundDuration(age, time.Second).String() + " ago)"
+}
+
+// Name returns metric name.
+func (t *Timestamp) Name() string {
+	return t.name
+}
+
+// Copy returns copy of timestamp with age at the copy time. It needs for snapshots.
+func (t *Timestamp) Copy() Metric {
+	return &Timestamp{
+		name:       t.name,
+		described:  t.described,
+		staleAfter: t.staleAfter,
+		value:      atomic.LoadInt64(&t.value),
+		frozen:     true,
+		frozenAt:   t.now(),
+	}
+}
+
+// ResetPolicy returns KeepValue, timestamps hold time of the last event and aren't flushed by snapshots.
+func (t *Timestamp) ResetPolicy() ResetPolicy {
+	return KeepValue
+}
+
+func (t *Timestamp) now() time.Time {
+	if t.frozen {
+		return t.frozenAt
+	}
+	return time.Now()
+}
+
+// roundDuration returns d rounded to the nearest multiple of m, halfway values are rounded away from zero.
+// It works as time.Duration.Round which isn't available before Go 1.9.
+func roundDuration(d, m time.Duration) time.Duration {
+	if d < 0 {
+		return -roundDuration(-d, m)
+	}
+	return (d + m/2) / m * m
+}
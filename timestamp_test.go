@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimestamp(t *testing.T) {
+	ts := NewTimestamp("ttimestamp", time.Minute)
+	if v := ts.Get().(float64); !math.IsNaN(v) {
+		t.Errorf("age of timestamp without events is expected to be NaN, but got %v", v)
+	}
+	if s := ts.String(); s != "never" {
+		t.Errorf("timestamp without events is expected to be shown as never, but got %s", s)
+	}
+	if !ts.Stale() {
+		t.Errorf("timestamp without events should be stale")
+	}
+
+	last := time.Now().Add(-5 * time.Minute)
+	ts.Set(last)
+	if v := ts.Get().(float64); v < 300 || v > 301 {
+		t.Errorf("age is expected to be about 300 seconds, but got %v", v)
+	}
+	if s := ts.String(); s != last.Format("2006-01-02 15:04:05")+" (5m0s ago)" {
+		t.Errorf("unexpected timestamp string %s", s)
+	}
+	if !ts.Stale() {
+		t.Errorf("timestamp older than stale period should be stale")
+	}
+
+	ts.Mark()
+	if ts.Stale() {
+		t.Errorf("fresh timestamp should not be stale")
+	}
+	cp := ts.Copy().(*Timestamp)
+	age := cp.Value()
+	time.Sleep(10 * time.Millisecond)
+	if v := cp.Value(); v != age {
+		t.Errorf("age of timestamp copy must not change, but got %v and %v", age, v)
+	}
+	if !strings.HasSuffix(cp.String(), "(0s ago)") {
+		t.Errorf("unexpected timestamp copy string %s", cp.String())
+	}
+}
+
+func TestTimestampSnapshots(t *testing.T) {
+	r, err := NewTrackRegistry("testTimestampSnapshots", 10, time.Hour, false)
+	if err != nil {
+		t.Fatalf("unable to create registry: %s", err)
+	}
+	tr := r.(*TrackRegistry)
+
+	ts := NewTimestamp("timestamp", time.Hour)
+	r.AddMetrics(ts)
+	ts.Mark()
+	tr.makeSnapshot()
+	tr.makeSnapshot()
+
+	if ts.Time().IsZero() {
+		t.Errorf("timestamp must not be reset by snapshots")
+	}
+	for i, s := range r.GetSnapshots() {
+		m, err := s.GetMetricByName("timestamp")
+		if err != nil {
+			t.Fatalf("unable to get metric from snapshot: %s", err)
+		}
+		if m.(*Timestamp).Time().IsZero() {
+			t.Errorf("snapshot %d should hold time of the last event", i)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package metrics
+
+import (
+	"bytes"
+	"container/heap"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// Number of counters kept by TopK per each reported key.
+// More counters give more accurate results for keys with close frequencies.
+const topKCapacityFactor = 10
+
+// TopKEntry is a key with its approximated count of observations.
+// Error is the maximal overestimation of count.
+type TopKEntry struct {
+	Key   string
+	Count uint64
+	Error uint64
+}
+
+// TopK is a metric that tracks the most frequent keys (heavy hitters) using Space-Saving algorithm.
+// It keeps a limited number of counters, so memory usage doesn't depend on the number of unique keys.
+// Satsfies Metric interface.
+type TopK struct {
+	name string
+	described
+	k        int
+	capacity int
+
+	mu    sync.Mutex
+	items map[string]*topKItem
+	heap  topKHeap
+}
+
+// NewTopK returns new top-k metric that satsfies Metric interface.
+// K is a number of reported keys, it's at least 1.
+func NewTopK(name string, k int, opts ...Option) *TopK {
+	if k < 1 {
+		k = 1
+	}
+	return &TopK{
+		name:      name,
+		described: newDescribed(KindUnknown, opts),
+		k:         k,
+		capacity:  k * topKCapacityFactor,
+		items:     make(map[string]*topKItem, k*topKCapacityFactor),
+	}
+}
+
+// Observe records a single observation of key.
+func (t *TopK) Observe(key string) {
+	t.Add(key, 1)
+}
+
+// Add records n observations of key.
+func (t *TopK) Add(key string, n uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if it, ok := t.items[key]; ok {
+		it.count += n
+		heap.Fix(&t.heap, it.index)
+		return
+	}
+
+	if len(t.heap) < t.capacity {
+		it := &topKItem{key: key, count: n}
+		t.items[key] = it
+		heap.Push(&t.heap, it)
+		return
+	}
+
+	// replace the least frequent key
+	it := t.heap[0]
+	delete(t.items, it.key)
+	it.key = key
+	it.err = it.count
+	it.count += n
+	t.items[key] = it
+	heap.Fix(&t.heap, 0)
+}
+
+// Get returns top-k entries.
+func (t *TopK) Get() interface{} {
+	return t.Top()
+}
+
+// Top returns up to k the most frequent keys ordered by count descending.
+func (t *TopK) Top() []TopKEntry {
+	t.mu.Lock()
+	entries := make([]TopKEntry, 0, len(t.heap))
+	for _, it := range t.heap {
+		entries = append(entries, TopKEntry{Key: it.key, Count: it.count, Error: it.err})
+	}
+	t.mu.Unlock()
+
+	sort.Sort(byCount(entries))
+	if len(entries) > t.k {
+		entries = entries[:t.k]
+	}
+	return entries
+}
+
+// String returns formated representation of top-k entries, e.g. "1. /index=120 2. /login=42".
+func (t *TopK) String() string {
+	var buf bytes.Buffer
+	for i, e := range t.Top() {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(strconv.Itoa(i + 1))
+		buf.WriteString(". ")
+		buf.WriteString(e.Key)
+		buf.WriteByte('=')
+		buf.WriteString(strconv.FormatUint(e.Count, 10))
+	}
+	return buf.String()
+}
+
+// Name returns metric name.
+func (t *TopK) Name() string {
+	return t.name
+}
+
+// Copy returns copy of top-k. It needs for snapshots.
+func (t *TopK) Copy() Metric {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	cp := &TopK{
+		name:      t.name,
+		described: t.described,
+		k:         t.k,
+		capacity:  t.capacity,
+		items:     make(map[string]*topKItem, len(t.items)),
+		heap:      make(topKHeap, len(t.heap)),
+	}
+	for i, it := range t.heap {
+		c := *it
+		cp.heap[i] = &c
+		cp.items[c.key] = &c
+	}
+	return cp
+}
+
+// Reset flushes top-k counters. It needs for snapshots.
+func (t *TopK) Reset() {
+	t.mu.Lock()
+	t.items = make(map[string]*topKItem, t.capacity)
+	t.heap = t.heap[:0]
+	t.mu.Unlock()
+}
+
+// Swap returns copy of top-k and flushes its counters atomically. It needs for snapshots.
+func (t *TopK) Swap() Metric {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	cp := &TopK{
+		name:      t.name,
+		described: t.described,
+		k:         t.k,
+		capacity:  t.capacity,
+		items:     t.items,
+		heap:      t.heap,
+	}
+	t.items = make(map[string]*topKItem, t.capacity)
+	t.heap = make(topKHeap, 0, t.capacity)
+	return cp
+}
+
+// topKItem is a counter of TopK.
+type topKItem struct {
+	key   string
+	count uint64
+	err   uint64
+	// index of item in the heap
+	index int
+}
+
+// topKHeap is a min-heap of counters ordered by count.
+type topKHeap []*topKItem
+
+func (h topKHeap) Len() int           { return len(h) }
+func (h topKHeap) Less(i, j int) bool { return h[i].count < h[j].count }
+func (h topKHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *topKHeap) Push(x interface{}) {
+	it := x.(*topKItem)
+	it.index = len(*h)
+	*h = append(*h, it)
+}
+
+func (h *topKHeap) Pop() interface{} {
+	old := *h
+	it := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return it
+}
+
+// byCount sorts entries by count descending and then by key.
+type byCount []TopKEntry
+
+func (s byCount) Len() int      { return len(s) }
+func (s byCount) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byCount) Less(i, j int) bool {
+	if s[i].Count != s[j].Count {
+		return s[i].Count > s[j].Count
+	}
+	return s[i].Key < s[j].Key
+}
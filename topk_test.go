@@ -0,0 +1,75 @@
+package metrics_test
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/admobi/easy-metrics"
+)
+
+func BenchmarkTopK(b *testing.B) {
+	tk := metrics.NewTopK("endpoints", 10)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tk.Observe(strconv.Itoa(i % 1000))
+	}
+}
+
+func TestTopK(t *testing.T) {
+	tk := metrics.NewTopK("ttopk", 3)
+
+	for i := 0; i < 100; i++ {
+		tk.Observe("/index")
+		if i%2 == 0 {
+			tk.Observe("/login")
+		}
+		if i%4 == 0 {
+			tk.Observe("/logout")
+		}
+		// a long tail of rare keys
+		tk.Observe("/user/" + strconv.Itoa(i))
+	}
+	tk.Add("/static", 30)
+
+	top := tk.Get().([]metrics.TopKEntry)
+	keys := make([]string, 0, len(top))
+	for _, e := range top {
+		keys = append(keys, e.Key)
+	}
+	if !reflect.DeepEqual(keys, []string{"/index", "/login", "/static"}) {
+		t.Errorf("top keys mismatch, got %v", top)
+	}
+	if top[0].Count < 100 || top[0].Count-top[0].Error > 100 {
+		t.Errorf("count of the most frequent key is out of error range: %v", top[0])
+	}
+
+	if s := metrics.NewTopK("empty", 3).String(); s != "" {
+		t.Errorf("empty top-k should have empty representation, got %s", s)
+	}
+}
+
+func TestTopKSnapshot(t *testing.T) {
+	rg, _ := metrics.NewTrackRegistry("testTopKSnapshot", 10, time.Second, false)
+	tk := metrics.NewTopK("topk", 2)
+	rg.AddMetrics(tk)
+
+	tk.Add("a", 3)
+	tk.Add("b", 2)
+	tk.Add("c", 1)
+
+	time.Sleep(time.Second + time.Millisecond*50)
+	if len(tk.Top()) != 0 {
+		t.Errorf("top-k should be flushed, got %v", tk.Top())
+	}
+
+	m, err := rg.GetSnapshots()[0].GetMetricByName("topk")
+	if err != nil {
+		t.Errorf("error on getting metric: %v", err)
+	}
+	if s := m.String(); s != "1. a=3 2. b=2" {
+		t.Errorf("unexpected string representation: %s", s)
+	}
+}
@@ -0,0 +1,277 @@
+//go:build go1.18
+// +build go1.18
+
+package metrics
+
+import (
+	"math"
+	"strconv"
+	"sync/atomic"
+)
+
+// Number is a constraint of values of typed metrics.
+type Number interface {
+	int64 | uint64 | float64
+}
+
+// TypedCounter is a cumulative metric that represents a single value of type T that only ever goes up.
+// Its Get method returns typed value, use Metric method to add it into a registry.
+type TypedCounter[T Number] struct {
+	// Value is accessed atomically, it goes first to be 64-bit aligned on 32-bit platforms
+	value typedValue[T]
+	name  string
+	described
+}
+
+// NewTypedCounter returns new typed counter.
+//
+//	c := NewTypedCounter[float64]("cpu_seconds")
+//	r.AddMetrics(c.Metric())
+func NewTypedCounter[T Number](name string, opts ...Option) *TypedCounter[T] {
+	return &TypedCounter[T]{name: name, described: newDescribed(KindCounter, opts)}
+}
+
+// Get returns counter value.
+func (c *TypedCounter[T]) Get() T {
+	return c.value.load()
+}
+
+// Value returns counter value as float64.
+func (c *TypedCounter[T]) Value() float64 {
+	return float64(c.value.load())
+}
+
+// Add adds delta to counter value. It returns ErrNegativeDelta if delta is negative or NaN,
+// counter value isn't changed in this case.
+func (c *TypedCounter[T]) Add(delta T) error {
+	if delta < 0 || delta != delta {
+		return ErrNegativeDelta(c.name)
+	}
+	c.value.add(delta)
+	return nil
+}
+
+// Inc increases counter value by 1
+func (c *TypedCounter[T]) Inc() {
+	c.value.add(1)
+}
+
+// String returns formated representation of counter value.
+func (c *TypedCounter[T]) String() string {
+	return formatNumber(c.described, c.value.load())
+}
+
+// Name returns metric name.
+func (c *TypedCounter[T]) Name() string {
+	return c.name
+}
+
+// Metric returns counter that satsfies Metric interface.
+func (c *TypedCounter[T]) Metric() Metric {
+	return typedCounterMetric[T]{c}
+}
+
+// Copy returns copy of counter. It needs for snapshots.
+func (c *TypedCounter[T]) Copy() Metric {
+	cp := &TypedCounter[T]{name: c.name, described: c.described}
+	cp.value.store(c.value.load())
+	return cp.Metric()
+}
+
+// Reset flushes counter value. It needs for snapshots.
+func (c *TypedCounter[T]) Reset() {
+	c.value.store(0)
+}
+
+// Swap returns copy of counter and flushes its value atomically. It needs for snapshots.
+func (c *TypedCounter[T]) Swap() Metric {
+	cp := &TypedCounter[T]{name: c.name, described: c.described}
+	cp.value.store(c.value.swap())
+	return cp.Metric()
+}
+
+// Diff returns counter with difference between counter and prev. It needs for cumulative snapshots.
+// If counter is less than prev (e.g. it was reset) the current value is returned.
+func (c *TypedCounter[T]) Diff(prev Metric) Metric {
+	value := c.value.load()
+	if pv, ok := prev.Get().(T); ok && pv <= value {
+		value -= pv
+	}
+	cp := &TypedCounter[T]{name: c.name, described: c.described}
+	cp.value.store(value)
+	return cp.Metric()
+}
+
+// typedCounterMetric is an adapter of typed counter to Metric interface.
+type typedCounterMetric[T Number] struct {
+	*TypedCounter[T]
+}
+
+// Get returns counter value.
+func (m typedCounterMetric[T]) Get() interface{} {
+	return m.TypedCounter.Get()
+}
+
+// TypedGauge is a metric that represents a single value of type T that can arbitrarily go up and down.
+// Its Get method returns typed value, use Metric method to add it into a registry.
+type TypedGauge[T Number] struct {
+	// Value is accessed atomically, it goes first to be 64-bit aligned on 32-bit platforms
+	value typedValue[T]
+	name  string
+	described
+}
+
+// NewTypedGauge returns new typed gauge.
+//
+//	g := NewTypedGauge[int64]("connections")
+//	r.AddMetrics(g.Metric())
+func NewTypedGauge[T Number](name string, opts ...Option) *TypedGauge[T] {
+	return &TypedGauge[T]{name: name, described: newDescribed(KindGauge, opts)}
+}
+
+// Get returns gauge value.
+func (g *TypedGauge[T]) Get() T {
+	return g.value.load()
+}
+
+// Value returns gauge value as float64.
+func (g *TypedGauge[T]) Value() float64 {
+	return float64(g.value.load())
+}
+
+// Add adds delta to gauge value.
+func (g *TypedGauge[T]) Add(delta T) {
+	g.value.add(delta)
+}
+
+// Sub substarcts delta from gauge value.
+func (g *TypedGauge[T]) Sub(delta T) {
+	g.value.add(-delta)
+}
+
+// Set sets gauge value to value.
+func (g *TypedGauge[T]) Set(value T) {
+	g.value.store(value)
+}
+
+// String returns formated representation of gauge value.
+func (g *TypedGauge[T]) String() string {
+	return formatNumber(g.described, g.value.load())
+}
+
+// Name returns metric name.
+func (g *TypedGauge[T]) Name() string {
+	return g.name
+}
+
+// Metric returns gauge that satsfies Metric interface.
+func (g *TypedGauge[T]) Metric() Metric {
+	return typedGaugeMetric[T]{g}
+}
+
+// Copy returns copy of gauge. It needs for snapshots.
+func (g *TypedGauge[T]) Copy() Metric {
+	cp := &TypedGauge[T]{name: g.name, described: g.described}
+	cp.value.store(g.value.load())
+	return cp.Metric()
+}
+
+// Reset flushes gauge value. It needs for snapshots.
+func (g *TypedGauge[T]) Reset() {
+	g.value.store(0)
+}
+
+// Swap returns copy of gauge and flushes its value atomically. It needs for snapshots.
+func (g *TypedGauge[T]) Swap() Metric {
+	cp := &TypedGauge[T]{name: g.name, described: g.described}
+	cp.value.store(g.value.swap())
+	return cp.Metric()
+}
+
+// ResetPolicy returns KeepValue, gauge holds the current value and isn't flushed by snapshots.
+func (g *TypedGauge[T]) ResetPolicy() ResetPolicy {
+	return KeepValue
+}
+
+// typedGaugeMetric is an adapter of typed gauge to Metric interface.
+type typedGaugeMetric[T Number] struct {
+	*TypedGauge[T]
+}
+
+// Get returns gauge value.
+func (m typedGaugeMetric[T]) Get() interface{} {
+	return m.TypedGauge.Get()
+}
+
+// typedValue is an atomic value of type T kept as bits in uint64.
+// Integers are updated with atomic addition, floats with CAS loop.
+type typedValue[T Number] struct {
+	bits uint64
+}
+
+func (v *typedValue[T]) load() T {
+	return fromBits[T](atomic.LoadUint64(&v.bits))
+}
+
+func (v *typedValue[T]) store(value T) {
+	atomic.StoreUint64(&v.bits, toBits(value))
+}
+
+func (v *typedValue[T]) swap() T {
+	return fromBits[T](atomic.SwapUint64(&v.bits, 0))
+}
+
+func (v *typedValue[T]) add(delta T) {
+	if _, ok := any(delta).(float64); !ok {
+		// two's complement addition works for both signed and unsigned integers
+		atomic.AddUint64(&v.bits, toBits(delta))
+		return
+	}
+	for {
+		cur := atomic.LoadUint64(&v.bits)
+		nxt := toBits(fromBits[T](cur) + delta)
+		if atomic.CompareAndSwapUint64(&v.bits, cur, nxt) {
+			return
+		}
+	}
+}
+
+// toBits returns bits of value.
+func toBits[T Number](value T) uint64 {
+	switch v := any(value).(type) {
+	case float64:
+		return math.Float64bits(v)
+	case int64:
+		return uint64(v)
+	case uint64:
+		return v
+	}
+	return 0
+}
+
+// fromBits returns value of bits.
+func fromBits[T Number](bits uint64) T {
+	var value T
+	switch v := any(&value).(type) {
+	case *float64:
+		*v = math.Float64frombits(bits)
+	case *int64:
+		*v = int64(bits)
+	case *uint64:
+		*v = bits
+	}
+	return value
+}
+
+// formatNumber returns value formatted by metric formatter.
+func formatNumber[T Number](d described, value T) string {
+	switch v := any(value).(type) {
+	case int64:
+		if d.format == nil {
+			return strconv.FormatInt(v, 10)
+		}
+	case uint64:
+		return d.formatUint(v)
+	}
+	return d.formatFloat(float64(value))
+}
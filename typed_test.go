@@ -0,0 +1,76 @@
+//go:build go1.18
+// +build go1.18
+
+package metrics_test
+
+import (
+	"testing"
+
+	"github.com/admobi/easy-metrics"
+)
+
+func TestTypedCounter(t *testing.T) {
+	c := metrics.NewTypedCounter[float64]("ttypedcounter")
+	c.Inc()
+	if err := c.Add(0.5); err != nil {
+		t.Errorf("unable to add delta: %s", err)
+	}
+	if _, ok := c.Add(-1).(metrics.ErrNegativeDelta); !ok {
+		t.Errorf("negative delta should be rejected with ErrNegativeDelta")
+	}
+	if v := c.Get(); v != 1.5 {
+		t.Errorf("counter value is expected to be 1.5, but got %v", v)
+	}
+
+	r, _ := metrics.NewRegistry("ttypedreg")
+	if err := r.AddMetrics(c.Metric()); err != nil {
+		t.Errorf("unable to add typed counter into registry: %s", err)
+	}
+	m, _ := r.GetMetricByName("ttypedcounter")
+	assertGauge(t, 1.5, m.Get())
+	if v, ok := metrics.ValueOf(m); !ok || v != 1.5 {
+		t.Errorf("counter numeric value is expected to be 1.5, but got %v", v)
+	}
+
+	cp := c.Swap()
+	assertGauge(t, 1.5, cp.Get())
+	if v := c.Get(); v != 0 {
+		t.Errorf("counter should be flushed by swap, but got %v", v)
+	}
+
+	u := metrics.NewTypedCounter[uint64]("ttypedcounter_uint")
+	u.Add(3)
+	u.Inc()
+	assertCounter(t, 4, u.Metric().Get())
+	if s := u.String(); s != "4" {
+		t.Errorf("counter string is expected to be 4, but got %s", s)
+	}
+}
+
+func TestTypedGauge(t *testing.T) {
+	g := metrics.NewTypedGauge[int64]("ttypedgauge")
+	g.Add(10)
+	g.Sub(15)
+	if v := g.Get(); v != -5 {
+		t.Errorf("gauge value is expected to be -5, but got %d", v)
+	}
+	if s := g.String(); s != "-5" {
+		t.Errorf("gauge string is expected to be -5, but got %s", s)
+	}
+
+	m := g.Metric()
+	if v := m.Get().(int64); v != -5 {
+		t.Errorf("gauge metric value is expected to be -5, but got %d", v)
+	}
+	if p, ok := m.(metrics.ResetPolicer); !ok || p.ResetPolicy() != metrics.KeepValue {
+		t.Errorf("gauge should keep value on snapshots")
+	}
+	if s, ok := m.(metrics.Snapshotter); !ok || s.Copy().Get().(int64) != -5 {
+		t.Errorf("gauge metric should be copied for snapshots")
+	}
+
+	f := metrics.NewTypedGauge[float64]("ttypedgauge_float")
+	f.Set(1.25)
+	f.Add(0.5)
+	assertGauge(t, 1.75, f.Metric().Get())
+}
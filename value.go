@@ -0,0 +1,73 @@
+package metrics
+
+// Valuer is implemented by metrics with a numeric value.
+type Valuer interface {
+	// Value returns the main metric value, e.g. counter value or count of histogram observations.
+	Value() float64
+}
+
+// Sample is a structured value of metric. Multi-valued metrics, e.g. histograms, keep their values in Fields.
+type Sample struct {
+	Name string
+	Kind Kind
+	// Value is the main metric value, the same as returned by Value method.
+	Value float64
+	// Fields are named values of multi-valued metric, e.g. "count", "sum" or "p99".
+	Fields map[string]float64
+}
+
+// Sampler is implemented by multi-valued metrics.
+type Sampler interface {
+	// Sample returns structured metric value.
+	Sample() Sample
+}
+
+// ValueOf returns numeric value of metric.
+// It uses Valuer and Sampler interfaces and falls back to numeric values returned by Get.
+// It returns false if metric has no numeric value.
+func ValueOf(m Metric) (float64, bool) {
+	switch v := m.(type) {
+	case Valuer:
+		return v.Value(), true
+	case Sampler:
+		return v.Sample().Value, true
+	}
+	return toFloat64(m.Get())
+}
+
+// SampleOf returns structured value of metric.
+// Metrics that don't implement Sampler are returned as a sample with a single value.
+// It returns false if metric has no numeric value.
+func SampleOf(m Metric) (Sample, bool) {
+	if s, ok := m.(Sampler); ok {
+		return s.Sample(), true
+	}
+	v, ok := ValueOf(m)
+	if !ok {
+		return Sample{}, false
+	}
+	return Sample{Name: m.Name(), Kind: metadataOf(m).Kind, Value: v}, true
+}
+
+// toFloat64 converts numeric value to float64.
+func toFloat64(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	}
+	return 0, false
+}
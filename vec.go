@@ -0,0 +1,324 @@
+package metrics
+
+import (
+	"bytes"
+	"strconv"
+	"sync"
+)
+
+// metricVec is a container of metrics partitioned by label values.
+// Child metrics are created lazily on first access.
+type metricVec struct {
+	name string
+	described
+	labelNames []string
+	// Options of vector are passed to each child metric
+	opts      []Option
+	newMetric func(name string, opts []Option) Metric
+
+	mu       sync.RWMutex
+	children map[string]*vecChild
+	// Ordered keys of children
+	orderedKeys []string
+}
+
+// vecChild is a child metric of vector with its label values.
+type vecChild struct {
+	values []string
+	metric Metric
+}
+
+func newMetricVec(name string, kind Kind, labelNames []string, newMetric func(name string, opts []Option) Metric) *metricVec {
+	return &metricVec{
+		name:       name,
+		described:  described{meta: Metadata{Kind: kind}},
+		labelNames: labelNames,
+		newMetric:  newMetric,
+		children:   make(map[string]*vecChild),
+	}
+}
+
+// setOptions sets options of vector and its future children.
+func (v *metricVec) setOptions(opts []Option) {
+	v.mu.Lock()
+	v.opts = append(v.opts, opts...)
+	v.described = newDescribed(v.meta.Kind, v.opts)
+	v.mu.Unlock()
+}
+
+// getWithLabelValues returns child metric for label values, it creates one if it doesn't exist.
+func (v *metricVec) getWithLabelValues(values []string) (Metric, error) {
+	if len(values) != len(v.labelNames) {
+		return nil, ErrLabelCardinality(v.name)
+	}
+	key := v.childName(values)
+
+	v.mu.RLock()
+	ch, ok := v.children[key]
+	v.mu.RUnlock()
+	if ok {
+		return ch.metric, nil
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if ch, ok := v.children[key]; ok {
+		return ch.metric, nil
+	}
+	ch = &vecChild{
+		values: append([]string(nil), values...),
+		metric: v.newMetric(key, v.opts),
+	}
+	v.children[key] = ch
+	v.orderedKeys = append(v.orderedKeys, key)
+	return ch.metric, nil
+}
+
+// childName returns name of child metric, e.g. requests{code="200",method="GET"}.
+func (v *metricVec) childName(values []string) string {
+	var buf bytes.Buffer
+	buf.WriteString(v.name)
+	buf.WriteByte('{')
+	for i, l := range v.labelNames {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(l)
+		buf.WriteByte('=')
+		buf.WriteString(strconv.Quote(values[i]))
+	}
+	buf.WriteByte('}')
+	return buf.String()
+}
+
+// LabelNames returns label names of vector.
+func (v *metricVec) LabelNames() []string {
+	return v.labelNames
+}
+
+// Name returns metric name.
+func (v *metricVec) Name() string {
+	return v.name
+}
+
+// String returns formated representation of child metrics, e.g. `{code="200"}=5 {code="500"}=1`.
+func (v *metricVec) String() string {
+	var buf bytes.Buffer
+	for i, ch := range v.list() {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(ch.metric.Name()[len(v.name):])
+		buf.WriteByte('=')
+		buf.WriteString(ch.metric.String())
+	}
+	return buf.String()
+}
+
+// Sample returns values of child metrics by their names, the sum of them is the main value.
+func (v *metricVec) Sample() Sample {
+	s := Sample{Name: v.name, Kind: v.meta.Kind, Fields: make(map[string]float64)}
+	for _, ch := range v.list() {
+		if val, ok := ValueOf(ch.metric); ok {
+			s.Fields[ch.metric.Name()] = val
+			s.Value += val
+		}
+	}
+	return s
+}
+
+// Exemplars returns exemplars of child metrics.
+func (v *metricVec) Exemplars() []Exemplar {
+	var ret []Exemplar
+	for _, ch := range v.list() {
+		ret = append(ret, exemplarsOf(ch.metric)...)
+	}
+	return ret
+}
+
+// list returns children in order of creation.
+func (v *metricVec) list() []*vecChild {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	ret := make([]*vecChild, 0, len(v.orderedKeys))
+	for _, k := range v.orderedKeys {
+		ret = append(ret, v.children[k])
+	}
+	return ret
+}
+
+// copyVec returns copy of vector with copies of all children.
+func (v *metricVec) copyVec() *metricVec {
+	return v.cloneVec(copyMetric)
+}
+
+// swapVec returns vector with copies of all children and flushes them.
+func (v *metricVec) swapVec() *metricVec {
+	return v.cloneVec(swapMetric)
+}
+
+// cloneVec returns copy of vector with children cloned by function clone.
+func (v *metricVec) cloneVec(clone func(Metric) Metric) *metricVec {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	cp := &metricVec{
+		name:        v.name,
+		described:   v.described,
+		labelNames:  v.labelNames,
+		opts:        v.opts,
+		newMetric:   v.newMetric,
+		children:    make(map[string]*vecChild, len(v.children)),
+		orderedKeys: append([]string(nil), v.orderedKeys...),
+	}
+	for k, ch := range v.children {
+		cp.children[k] = &vecChild{values: ch.values, metric: clone(ch.metric)}
+	}
+	return cp
+}
+
+func (v *metricVec) vec() *metricVec {
+	return v
+}
+
+// CounterVec is a set of counters partitioned by label values, e.g. requests by HTTP status code.
+// It's registered as a single metric.
+// Satsfies Metric interface.
+type CounterVec struct {
+	*metricVec
+}
+
+// NewCounterVec returns new counter vector with given label names that satsfies Metric interface.
+func NewCounterVec(name string, labelNames ...string) *CounterVec {
+	return &CounterVec{
+		newMetricVec(name, KindCounter, labelNames, func(name string, opts []Option) Metric { return NewCounter(name, opts...) }),
+	}
+}
+
+// WithOptions sets options of vector and its children. It should be called before the vector is used.
+//
+//	v := NewCounterVec("requests", "code").WithOptions(WithUnit("requests"))
+func (v *CounterVec) WithOptions(opts ...Option) *CounterVec {
+	v.setOptions(opts)
+	return v
+}
+
+// GetMetricWithLabelValues returns counter for given label values, it creates one if it doesn't exist.
+// Number of values must be the same as number of label names.
+func (v *CounterVec) GetMetricWithLabelValues(values ...string) (*Counter, error) {
+	m, err := v.getWithLabelValues(values)
+	if err != nil {
+		return nil, err
+	}
+	return m.(*Counter), nil
+}
+
+// WithLabelValues works as GetMetricWithLabelValues, but panics on error.
+//
+//	v.WithLabelValues("200").Inc()
+func (v *CounterVec) WithLabelValues(values ...string) *Counter {
+	c, err := v.GetMetricWithLabelValues(values...)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Get returns map of child counter names and their values.
+func (v *CounterVec) Get() interface{} {
+	ret := make(map[string]uint64)
+	for _, ch := range v.list() {
+		ret[ch.metric.Name()] = ch.metric.Get().(uint64)
+	}
+	return ret
+}
+
+// Copy returns copy of counter vector. It needs for snapshots.
+func (v *CounterVec) Copy() Metric {
+	return &CounterVec{v.copyVec()}
+}
+
+// Swap returns copy of counter vector and flushes its children. It needs for snapshots.
+func (v *CounterVec) Swap() Metric {
+	return &CounterVec{v.swapVec()}
+}
+
+// Diff returns counter vector with differences between its children and children of prev.
+// It needs for cumulative snapshots.
+func (v *CounterVec) Diff(prev Metric) Metric {
+	cp := v.copyVec()
+	if p, ok := prev.(*CounterVec); ok {
+		for k, ch := range cp.children {
+			if pch, ok := p.children[k]; ok {
+				ch.metric = ch.metric.(*Counter).Diff(pch.metric)
+			}
+		}
+	}
+	return &CounterVec{cp}
+}
+
+// GaugeVec is a set of gauges partitioned by label values, e.g. queue length by queue name.
+// It's registered as a single metric.
+// Satsfies Metric interface.
+type GaugeVec struct {
+	*metricVec
+}
+
+// NewGaugeVec returns new gauge vector with given label names that satsfies Metric interface.
+func NewGaugeVec(name string, labelNames ...string) *GaugeVec {
+	return &GaugeVec{
+		newMetricVec(name, KindGauge, labelNames, func(name string, opts []Option) Metric { return NewGauge(name, opts...) }),
+	}
+}
+
+// WithOptions sets options of vector and its children. It should be called before the vector is used.
+//
+//	v := NewGaugeVec("queue", "name").WithOptions(WithUnit("messages"))
+func (v *GaugeVec) WithOptions(opts ...Option) *GaugeVec {
+	v.setOptions(opts)
+	return v
+}
+
+// GetMetricWithLabelValues returns gauge for given label values, it creates one if it doesn't exist.
+// Number of values must be the same as number of label names.
+func (v *GaugeVec) GetMetricWithLabelValues(values ...string) (*Gauge, error) {
+	m, err := v.getWithLabelValues(values)
+	if err != nil {
+		return nil, err
+	}
+	return m.(*Gauge), nil
+}
+
+// WithLabelValues works as GetMetricWithLabelValues, but panics on error.
+//
+//	v.WithLabelValues("emails").Set(42)
+func (v *GaugeVec) WithLabelValues(values ...string) *Gauge {
+	g, err := v.GetMetricWithLabelValues(values...)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// Get returns map of child gauge names and their values.
+func (v *GaugeVec) Get() interface{} {
+	ret := make(map[string]float64)
+	for _, ch := range v.list() {
+		ret[ch.metric.Name()] = ch.metric.Get().(float64)
+	}
+	return ret
+}
+
+// Copy returns copy of gauge vector. It needs for snapshots.
+func (v *GaugeVec) Copy() Metric {
+	return &GaugeVec{v.copyVec()}
+}
+
+// Swap returns copy of gauge vector and flushes its children. It needs for snapshots.
+func (v *GaugeVec) Swap() Metric {
+	return &GaugeVec{v.swapVec()}
+}
+
+// ResetPolicy returns KeepValue, gauges hold the current values and aren't flushed by snapshots.
+func (v *GaugeVec) ResetPolicy() ResetPolicy {
+	return KeepValue
+}
@@ -0,0 +1,77 @@
+package metrics_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/admobi/easy-metrics"
+)
+
+func TestCounterVec(t *testing.T) {
+	v := metrics.NewCounterVec("requests", "code", "method")
+
+	v.WithLabelValues("200", "GET").Inc()
+	v.WithLabelValues("200", "GET").Inc()
+	v.WithLabelValues("500", "POST").Add(3)
+
+	expected := map[string]uint64{
+		`requests{code="200",method="GET"}`:  2,
+		`requests{code="500",method="POST"}`: 3,
+	}
+	if !reflect.DeepEqual(expected, v.Get()) {
+		t.Errorf("counter vector mismatch, expected %v, but got %v", expected, v.Get())
+	}
+	if s := v.String(); s != `{code="200",method="GET"}=2 {code="500",method="POST"}=3` {
+		t.Errorf("unexpected string representation: %s", s)
+	}
+
+	_, err := v.GetMetricWithLabelValues("200")
+	switch err.(type) {
+	case metrics.ErrLabelCardinality:
+	default:
+		t.Errorf("should be label cardinality error, but got %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("WithLabelValues should panic on label cardinality mismatch")
+		}
+	}()
+	v.WithLabelValues("200", "GET", "extra")
+}
+
+func TestGaugeVec(t *testing.T) {
+	v := metrics.NewGaugeVec("queue", "name")
+	v.WithLabelValues("emails").Set(42)
+	v.WithLabelValues("sms").Add(1.5)
+	v.WithLabelValues("emails").Sub(2)
+
+	expected := map[string]float64{`queue{name="emails"}`: 40, `queue{name="sms"}`: 1.5}
+	if !reflect.DeepEqual(expected, v.Get()) {
+		t.Errorf("gauge vector mismatch, expected %v, but got %v", expected, v.Get())
+	}
+}
+
+func TestCounterVecSnapshot(t *testing.T) {
+	rg, _ := metrics.NewTrackRegistry("testCounterVecSnapshot", 10, time.Second, false)
+	v := metrics.NewCounterVec("requests", "code")
+	if err := rg.AddMetrics(v); err != nil {
+		t.Errorf("unable to register vector: %v", err)
+	}
+
+	v.WithLabelValues("200").Add(10)
+	v.WithLabelValues("404").Add(2)
+
+	time.Sleep(time.Second + time.Millisecond*50)
+	assertCounter(t, 0, v.WithLabelValues("200").Get())
+
+	m, err := rg.GetSnapshots()[0].GetMetricByName("requests")
+	if err != nil {
+		t.Errorf("error on getting metric: %v", err)
+	}
+	expected := map[string]uint64{`requests{code="200"}`: 10, `requests{code="404"}`: 2}
+	if !reflect.DeepEqual(expected, m.Get()) {
+		t.Errorf("snapshot mismatch, expected %v, but got %v", expected, m.Get())
+	}
+}